@@ -0,0 +1,77 @@
+package monaddb
+
+// TotalBytesWritten reports how much data the database has written since
+// it was opened. logicalBytes is the sum of all value sizes submitted
+// through Upsert (deletes carry no value and count as zero). physicalBytes
+// is the on-disk footprint of every storage tier plus the large-value
+// sidecar — node headers, version history and compaction residue
+// included — so physicalBytes / logicalBytes approximates the write
+// amplification the SSD sees. In-memory databases have no physical side
+// and return ErrNotSupported.
+func (db *DB) TotalBytesWritten() (logicalBytes, physicalBytes uint64, err error) {
+	if db == nil || db.ptr == nil {
+		return 0, 0, ErrNullPointer
+	}
+	if db.path == "" {
+		return 0, 0, ErrNotSupported
+	}
+
+	logicalBytes = db.logicalWritten.Load()
+
+	hot, warm, cold, err := db.TierStats()
+	if err != nil {
+		return 0, 0, err
+	}
+	physicalBytes = hot.Bytes + warm.Bytes + cold.Bytes
+
+	if db.external != nil {
+		sidecar, err := measurePath(externalStorePath(db.path))
+		if err != nil {
+			return 0, 0, err
+		}
+		physicalBytes += sidecar.Bytes
+	}
+	return logicalBytes, physicalBytes, nil
+}
+
+// SpaceAmplification reports how much bigger the on-disk footprint is
+// than the live data it stores: physical disk bytes divided by the key
+// and value bytes reachable from the latest version. 1.0 means no
+// overhead; growing values indicate fragmentation or version history
+// outliving its usefulness — above roughly 3.0 is worth an alert. The
+// live size comes from a scan of the latest trie, so this is a
+// maintenance-window metric, not a hot-path one. In-memory databases
+// return ErrNotSupported; a database with no live data has no meaningful
+// ratio and returns ErrInvalidArgument.
+func (db *DB) SpaceAmplification() (float64, error) {
+	if db == nil || db.ptr == nil {
+		return 0, ErrNullPointer
+	}
+	if db.path == "" {
+		return 0, ErrNotSupported
+	}
+
+	version := db.LatestVersion()
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		return 0, err
+	}
+
+	var liveBytes uint64
+	err = db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		liveBytes += uint64(len(kv.Key) + len(kv.Value))
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if liveBytes == 0 {
+		return 0, ErrInvalidArgument
+	}
+
+	_, physical, err := db.TotalBytesWritten()
+	if err != nil {
+		return 0, err
+	}
+	return float64(physical) / float64(liveBytes), nil
+}
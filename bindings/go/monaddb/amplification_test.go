@@ -0,0 +1,71 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestTotalBytesWritten tests the logical and physical write counters.
+func TestTotalBytesWritten(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/amp.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), make([]byte, 100), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := db.Delete(root, makeKey32(0x01), 2); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	logical, physical, err := db.TotalBytesWritten()
+	if err != nil {
+		t.Fatalf("TotalBytesWritten failed: %v", err)
+	}
+	if logical != 100 {
+		t.Errorf("Expected 100 logical bytes (deletes carry none), got %d", logical)
+	}
+	if physical == 0 {
+		t.Error("A disk database should have a physical footprint")
+	}
+}
+
+// TestTotalBytesWrittenMemory tests that the metric needs a disk database.
+func TestTotalBytesWrittenMemory(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, _, err := db.TotalBytesWritten(); err != ErrNotSupported {
+		t.Errorf("In-memory databases should be unsupported, got %v", err)
+	}
+}
+
+// TestSpaceAmplification tests the footprint-to-live-data ratio.
+func TestSpaceAmplification(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/space.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.SpaceAmplification(); err != ErrInvalidArgument {
+		t.Errorf("An empty database has no ratio, got %v", err)
+	}
+
+	if _, err := db.Put(nil, makeKey32(0x01), make([]byte, 1000), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	ratio, err := db.SpaceAmplification()
+	if err != nil {
+		t.Fatalf("SpaceAmplification failed: %v", err)
+	}
+	if ratio < 1.0 {
+		t.Errorf("Footprint cannot be smaller than live data, got %v", ratio)
+	}
+}
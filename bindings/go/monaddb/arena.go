@@ -0,0 +1,97 @@
+package monaddb
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+*/
+import "C"
+import "unsafe"
+
+// WriteArena is a bump allocator over a single C memory region. Using one
+// for Upsert marshalling turns the O(n) malloc/free per batch (one per
+// key and value) into a single allocation amortized over the arena's
+// lifetime: allocations bump a pointer, Reset rewinds it.
+//
+// A WriteArena is not safe for concurrent use, matching the DB's
+// single-writer model. Free releases the region; the arena must not be
+// used afterwards.
+type WriteArena struct {
+	base unsafe.Pointer
+	size uintptr
+	off  uintptr
+}
+
+// NewWriteArena allocates an arena of the given byte size in C memory.
+// Returns nil if size is not positive or the allocation fails.
+func NewWriteArena(size int) *WriteArena {
+	if size <= 0 {
+		return nil
+	}
+	base := C.malloc(C.size_t(size))
+	if base == nil {
+		return nil
+	}
+	return &WriteArena{base: base, size: uintptr(size)}
+}
+
+// alloc bump-allocates n bytes, 8-byte aligned. Returns nil when the
+// arena cannot satisfy the request; callers fall back to C.malloc.
+func (a *WriteArena) alloc(n uintptr) unsafe.Pointer {
+	off := (a.off + 7) &^ 7
+	if n == 0 || off+n > a.size {
+		return nil
+	}
+	a.off = off + n
+	return unsafe.Pointer(uintptr(a.base) + off)
+}
+
+// AllocKey copies key into the arena and returns the C pointer to the
+// copy. Returns nil when the arena is full; the pointer stays valid until
+// Reset or Free.
+func (a *WriteArena) AllocKey(key []byte) *C.uint8_t {
+	return a.allocBytes(key)
+}
+
+// AllocValue copies value into the arena, with the same contract as
+// AllocKey.
+func (a *WriteArena) AllocValue(value []byte) *C.uint8_t {
+	return a.allocBytes(value)
+}
+
+func (a *WriteArena) allocBytes(data []byte) *C.uint8_t {
+	if len(data) == 0 {
+		return nil
+	}
+	p := a.alloc(uintptr(len(data)))
+	if p == nil {
+		return nil
+	}
+	C.memcpy(p, unsafe.Pointer(&data[0]), C.size_t(len(data)))
+	return (*C.uint8_t)(p)
+}
+
+// Reset rewinds the bump pointer without freeing the region, invalidating
+// every pointer handed out since the last Reset. Call it after the Upsert
+// using the arena's memory has returned.
+func (a *WriteArena) Reset() {
+	a.off = 0
+}
+
+// Free releases the underlying C region.
+func (a *WriteArena) Free() {
+	if a.base != nil {
+		C.free(a.base)
+		a.base = nil
+		a.size = 0
+		a.off = 0
+	}
+}
+
+// SetWriteArena installs an arena used to marshal Upsert batches. The
+// arena is reset automatically after each Upsert returns. Passing nil
+// reverts to per-buffer C.malloc. The caller keeps ownership of the
+// arena and must Free it after detaching it (or closing the DB).
+func (db *DB) SetWriteArena(a *WriteArena) {
+	db.writeArena = a
+}
@@ -0,0 +1,78 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWriteArena tests bump allocation, reset and the Upsert integration.
+func TestWriteArena(t *testing.T) {
+	arena := NewWriteArena(1 << 16)
+	if arena == nil {
+		t.Fatal("NewWriteArena failed")
+	}
+	defer arena.Free()
+
+	p := arena.AllocKey([]byte("abc"))
+	if p == nil {
+		t.Fatal("AllocKey returned nil on a fresh arena")
+	}
+	offAfterFirst := arena.off
+	if arena.AllocValue([]byte("def")) == nil {
+		t.Fatal("AllocValue returned nil on a fresh arena")
+	}
+	if arena.off <= offAfterFirst {
+		t.Error("Allocations should advance the bump pointer")
+	}
+
+	arena.Reset()
+	if arena.off != 0 {
+		t.Error("Reset should rewind the bump pointer")
+	}
+
+	// An exhausted arena returns nil so callers can fall back to malloc
+	small := NewWriteArena(8)
+	defer small.Free()
+	if small.AllocKey(make([]byte, 64)) != nil {
+		t.Error("Allocation beyond the arena size should return nil")
+	}
+
+	// Upserts through an installed arena produce the same trie
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	db.SetWriteArena(arena)
+	value := []byte("arena-backed value")
+	root, err := db.Put(nil, makeKey32(0x01), value, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if arena.off != 0 {
+		t.Error("Upsert should reset the arena when done")
+	}
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	got, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Errorf("Arena-marshalled value mismatch: got %q", got)
+	}
+	db.SetWriteArena(nil)
+}
+
+// TestNewWriteArenaInvalidSize tests the degenerate size guard.
+func TestNewWriteArenaInvalidSize(t *testing.T) {
+	if NewWriteArena(0) != nil {
+		t.Error("NewWriteArena(0) should return nil")
+	}
+	if NewWriteArena(-1) != nil {
+		t.Error("NewWriteArena(-1) should return nil")
+	}
+}
@@ -0,0 +1,150 @@
+package monaddb
+
+import (
+	"sync"
+	"time"
+)
+
+// autoBatcher queues single-key writes and flushes them as one Upsert
+// when the batch grows to maxSize or maxDelay elapses since the first
+// queued write, whichever comes first.
+type autoBatcher struct {
+	db       *DB
+	maxDelay time.Duration
+	maxSize  int
+
+	mu           sync.Mutex
+	pending      []Update
+	pendingBytes int
+	root         *Node // root the next flush applies on top of
+	version      uint64
+	timer        *time.Timer
+	lastErr      error // error from the most recent flush, reported once
+}
+
+// enqueue queues one update. root and version from the caller replace the
+// ones the next flush will use, so the newest view wins. Returns the error
+// from a previous background flush, if one failed since the last call.
+func (b *autoBatcher) enqueue(root *Node, u Update, version uint64) error {
+	b.mu.Lock()
+	if root != nil {
+		b.root = root
+	}
+	b.version = version
+	b.pending = append(b.pending, u)
+	b.pendingBytes += len(u.Key) + len(u.Value)
+	b.db.addWriteLag(1, int64(len(u.Key)+len(u.Value)))
+
+	err := b.lastErr
+	b.lastErr = nil
+
+	if len(b.pending) >= b.maxSize {
+		b.flushLocked()
+		b.mu.Unlock()
+		return err
+	}
+	if b.timer == nil && b.maxDelay > 0 {
+		b.timer = time.AfterFunc(b.maxDelay, func() {
+			b.mu.Lock()
+			b.flushLocked()
+			b.mu.Unlock()
+		})
+	}
+	b.mu.Unlock()
+	return err
+}
+
+// flushLocked applies the queued updates in one Upsert. Called with b.mu
+// held; the lock stays held across the Upsert so queued order is preserved.
+func (b *autoBatcher) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+
+	updates := b.pending
+	bytes := b.pendingBytes
+	b.pending = nil
+	b.pendingBytes = 0
+
+	root, err := b.db.Upsert(b.root, updates, b.version)
+	b.db.addWriteLag(-int64(len(updates)), -int64(bytes))
+	if err != nil {
+		b.db.log().Error("auto-batch flush failed", "updates", len(updates), "version", b.version, "error", err)
+		b.lastErr = err
+		return
+	}
+	b.db.log().Debug("auto-batch flushed", "updates", len(updates), "version", b.version)
+	b.root = root
+}
+
+// flush synchronously applies any queued updates and returns the
+// resulting root along with any flush error.
+func (b *autoBatcher) flush() (*Node, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked()
+	err := b.lastErr
+	b.lastErr = nil
+	return b.root, err
+}
+
+// abandon drops all queued updates without applying them and returns how
+// many were discarded. Used by Close, where flushing is no longer possible.
+func (b *autoBatcher) abandon() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	lost := uint64(len(b.pending))
+	b.db.addWriteLag(-int64(len(b.pending)), -int64(b.pendingBytes))
+	b.pending = nil
+	b.pendingBytes = 0
+	return lost
+}
+
+// SetAutoBatch enables transparent write batching: subsequent Put and
+// Delete calls are queued in Go memory and applied as a single Upsert once
+// maxSize writes have accumulated or maxDelay has passed since the first
+// queued write. While batching is active, Put and Delete return a nil root
+// immediately; the actual root advances at flush time and is threaded into
+// the next flush automatically.
+//
+// Calling SetAutoBatch with maxSize <= 0 disables batching, flushing any
+// queued writes first and returning the flush error.
+func (db *DB) SetAutoBatch(maxDelay time.Duration, maxSize int) error {
+	if maxSize <= 0 {
+		if old := db.autoBatch.Swap(nil); old != nil {
+			_, err := old.flush()
+			return err
+		}
+		return nil
+	}
+
+	b := &autoBatcher{db: db, maxDelay: maxDelay, maxSize: maxSize}
+	if old := db.autoBatch.Swap(b); old != nil {
+		root, err := old.flush()
+		b.mu.Lock()
+		b.root = root
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// BatchPending returns the number of writes currently queued by the
+// auto-batcher. Zero when auto-batching is disabled.
+func (db *DB) BatchPending() int {
+	b := db.autoBatch.Load()
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
@@ -0,0 +1,142 @@
+package monaddb
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAutoBatchSizeFlush tests that the queue flushes when maxSize is hit.
+func TestAutoBatchSizeFlush(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetAutoBatch(time.Hour, 3); err != nil {
+		t.Fatalf("SetAutoBatch failed: %v", err)
+	}
+
+	for i := byte(1); i <= 2; i++ {
+		root, err := db.Put(nil, makeKey32(i), []byte("value"), 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if root != nil {
+			t.Error("Queued Put should return a nil root")
+		}
+	}
+	if pending := db.BatchPending(); pending != 2 {
+		t.Errorf("BatchPending should be 2, got %d", pending)
+	}
+	ops, _, err := db.WriteLag()
+	if err != nil {
+		t.Fatalf("WriteLag failed: %v", err)
+	}
+	if ops != 2 {
+		t.Errorf("WriteLag ops should be 2, got %d", ops)
+	}
+
+	// The third write reaches maxSize and triggers a flush
+	if _, err := db.Put(nil, makeKey32(3), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if pending := db.BatchPending(); pending != 0 {
+		t.Errorf("BatchPending should be 0 after flush, got %d", pending)
+	}
+	ops, bytes, err := db.WriteLag()
+	if err != nil {
+		t.Fatalf("WriteLag failed: %v", err)
+	}
+	if ops != 0 || bytes != 0 {
+		t.Errorf("WriteLag should be zero after flush, got ops=%d bytes=%d", ops, bytes)
+	}
+
+	// All three keys are visible under the root the flush produced
+	root, err := db.autoBatch.Load().flush()
+	if err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	for i := byte(1); i <= 3; i++ {
+		node, err := db.FindFromRoot(root, makeKey32(i), 1)
+		if err != nil {
+			t.Fatalf("FindFromRoot failed: %v", err)
+		}
+		if node == nil || !node.HasValue() {
+			t.Errorf("Key %d should be present after flush", i)
+		}
+	}
+
+	if err := db.SetAutoBatch(0, 0); err != nil {
+		t.Fatalf("SetAutoBatch(0, 0) failed: %v", err)
+	}
+}
+
+// TestAutoBatchDelayFlush tests that the queue flushes after maxDelay.
+func TestAutoBatchDelayFlush(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetAutoBatch(10*time.Millisecond, 1000); err != nil {
+		t.Fatalf("SetAutoBatch failed: %v", err)
+	}
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if pending := db.BatchPending(); pending != 1 {
+		t.Errorf("BatchPending should be 1, got %d", pending)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for db.BatchPending() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the delay-triggered flush")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := db.SetAutoBatch(0, 0); err != nil {
+		t.Fatalf("SetAutoBatch(0, 0) failed: %v", err)
+	}
+}
+
+// TestCloseReportsLostWrites tests the Close pending-write count.
+func TestCloseReportsLostWrites(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+
+	if err := db.SetAutoBatch(time.Hour, 1000); err != nil {
+		t.Fatalf("SetAutoBatch failed: %v", err)
+	}
+	for i := byte(1); i <= 3; i++ {
+		if _, err := db.Put(nil, makeKey32(i), []byte{i}, 1); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	lost, err := db.Close()
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if lost != 3 {
+		t.Errorf("Close should report 3 lost writes, got %d", lost)
+	}
+
+	// A flushed database loses nothing
+	db2, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	lost, err = db2.Close()
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if lost != 0 {
+		t.Errorf("Close without pending writes should report 0, got %d", lost)
+	}
+}
@@ -0,0 +1,112 @@
+package monaddb
+
+import "sort"
+
+// BatchWriter accumulates updates in Go memory and applies them in a
+// single Upsert on Commit. This follows the BeginBatch/Commit idiom from
+// databases like RocksDB: buffering is free of C-layer work until Commit.
+//
+// A BatchWriter must not be used after Commit or Discard.
+type BatchWriter struct {
+	db      *DB
+	version uint64
+	updates []Update
+}
+
+// BeginBatch starts a new write batch targeting the given version.
+func (db *DB) BeginBatch(version uint64) *BatchWriter {
+	return &BatchWriter{db: db, version: version}
+}
+
+// Put queues a key-value insertion into the batch.
+func (bw *BatchWriter) Put(key, value []byte) {
+	bw.updates = append(bw.updates, Update{Type: UpdatePut, Key: key, Value: value})
+}
+
+// Delete queues a key deletion into the batch.
+func (bw *BatchWriter) Delete(key []byte) {
+	bw.updates = append(bw.updates, Update{Type: UpdateDelete, Key: key})
+}
+
+// Len returns the number of queued updates.
+func (bw *BatchWriter) Len() int {
+	return len(bw.updates)
+}
+
+// Commit applies all queued updates on top of root in one Upsert and
+// returns the new root. An empty batch returns root unchanged.
+func (bw *BatchWriter) Commit(root *Node) (*Node, error) {
+	if bw.db == nil {
+		return nil, ErrNullPointer
+	}
+	updates := bw.updates
+	bw.Discard()
+	return bw.db.Upsert(root, updates, bw.version)
+}
+
+// Discard drops all queued updates without applying them.
+func (bw *BatchWriter) Discard() {
+	bw.updates = nil
+}
+
+// UpsertMany applies each batch in order on top of root, all at the same
+// version, and returns the root after the last batch. Every batch commits
+// atomically on its own; if one fails, the root produced by the last
+// successful batch is returned alongside the error, so a block processor
+// can tell exactly which transaction group was left unapplied.
+func (db *DB) UpsertMany(root *Node, batches [][]Update, version uint64) (*Node, error) {
+	for _, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+		next, err := db.Upsert(root, batch, version)
+		if err != nil {
+			return root, err
+		}
+		root = next
+	}
+	return root, nil
+}
+
+// UpsertVersionRange replays a chain of state transitions in one call:
+// each map entry is applied at its own version, in ascending version order,
+// on top of the previous result. Returns the root after the last version and
+// the last version that was actually applied (zero if none were). On
+// failure the partially-applied root and version are returned with the
+// error, leaving the caller positioned to resume the replay.
+func (db *DB) UpsertVersionRange(root *Node, versionedUpdates map[uint64][]Update) (*Node, uint64, error) {
+	versions := make([]uint64, 0, len(versionedUpdates))
+	for v := range versionedUpdates {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	var applied uint64
+	for _, v := range versions {
+		updates := versionedUpdates[v]
+		if len(updates) == 0 {
+			continue
+		}
+		next, err := db.Upsert(root, updates, v)
+		if err != nil {
+			return root, applied, err
+		}
+		root = next
+		applied = v
+	}
+	return root, applied, nil
+}
+
+// BatchDelete removes a set of keys in one Upsert, building the
+// UpdateDelete entries internally so state-expiry sweeps do not have to.
+// An empty key slice returns root unchanged.
+func (db *DB) BatchDelete(root *Node, keys [][]byte, version uint64) (*Node, error) {
+	if len(keys) == 0 {
+		return root, nil
+	}
+	updates := make([]Update, len(keys))
+	for i, key := range keys {
+		updates[i] = Update{Type: UpdateDelete, Key: key}
+	}
+	return db.Upsert(root, updates, version)
+}
@@ -0,0 +1,195 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestBatchWriter tests the BeginBatch/Commit write path.
+func TestBatchWriter(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	keyA := makeKey32(0x0A)
+	keyB := makeKey32(0x0B)
+
+	bw := db.BeginBatch(1)
+	bw.Put(keyA, []byte("a"))
+	bw.Put(keyB, []byte("b"))
+	bw.Delete(keyA)
+	if bw.Len() != 3 {
+		t.Errorf("Batch length should be 3, got %d", bw.Len())
+	}
+
+	root, err := bw.Commit(nil)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if bw.Len() != 0 {
+		t.Errorf("Batch should be empty after commit, got %d", bw.Len())
+	}
+
+	node, err := db.FindFromRoot(root, keyB, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("b")) {
+		t.Errorf("Value mismatch: got %q", value)
+	}
+
+	node, err = db.FindFromRoot(root, keyA, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	if node != nil && node.HasValue() {
+		t.Error("Key a was deleted within the batch and should not be found")
+	}
+}
+
+// TestBatchWriterDiscard tests that discarded batches apply nothing.
+func TestBatchWriterDiscard(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	bw := db.BeginBatch(1)
+	bw.Put(makeKey32(0x01), []byte("value"))
+	bw.Discard()
+	if bw.Len() != 0 {
+		t.Errorf("Batch should be empty after discard, got %d", bw.Len())
+	}
+
+	// An empty commit returns the root unchanged
+	root, err := bw.Commit(nil)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if root != nil {
+		t.Error("Committing an empty batch on a nil root should return nil")
+	}
+}
+
+// TestUpsertMany tests sequential batch application at one version.
+func TestUpsertMany(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	batches := [][]Update{
+		{
+			{Type: UpdatePut, Key: makeKey32(0x01), Value: []byte("one")},
+			{Type: UpdatePut, Key: makeKey32(0x02), Value: []byte("two")},
+		},
+		nil,
+		{
+			{Type: UpdatePut, Key: makeKey32(0x03), Value: []byte("three")},
+			{Type: UpdateDelete, Key: makeKey32(0x01)},
+		},
+	}
+
+	root, err := db.UpsertMany(nil, batches, 1)
+	if err != nil {
+		t.Fatalf("UpsertMany failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, makeKey32(0x03), 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("three")) {
+		t.Errorf("Value mismatch: got %q", value)
+	}
+
+	node, err = db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	if node != nil && node.HasValue() {
+		t.Error("Key deleted by a later batch should not be found")
+	}
+}
+
+// TestUpsertVersionRange tests ascending replay of versioned updates.
+func TestUpsertVersionRange(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	updates := map[uint64][]Update{
+		3: {{Type: UpdatePut, Key: key, Value: []byte("v3")}},
+		1: {{Type: UpdatePut, Key: key, Value: []byte("v1")}},
+		2: {{Type: UpdatePut, Key: key, Value: []byte("v2")}},
+	}
+
+	root, last, err := db.UpsertVersionRange(nil, updates)
+	if err != nil {
+		t.Fatalf("UpsertVersionRange failed: %v", err)
+	}
+	if last != 3 {
+		t.Errorf("Last applied version should be 3, got %d", last)
+	}
+
+	node, err := db.FindFromRoot(root, key, 3)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("v3")) {
+		t.Errorf("Final value should come from the highest version, got %q", value)
+	}
+}
+
+// TestBatchDelete tests deleting a key set in one call.
+func TestBatchDelete(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Key: makeKey32(0x01), Value: []byte("one")},
+		{Key: makeKey32(0x02), Value: []byte("two")},
+		{Key: makeKey32(0x03), Value: []byte("three")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	newRoot, err := db.BatchDelete(root, [][]byte{makeKey32(0x01), makeKey32(0x03)}, 2)
+	if err != nil {
+		t.Fatalf("BatchDelete failed: %v", err)
+	}
+	if gone, err := db.FindFromRoot(newRoot, makeKey32(0x01), 2); err != nil || gone != nil {
+		t.Errorf("Deleted key should be gone, got %v, %v", gone, err)
+	}
+	if _, err := db.FindFromRoot(newRoot, makeKey32(0x02), 2); err != nil {
+		t.Errorf("Untouched key should survive, got %v", err)
+	}
+
+	if same, err := db.BatchDelete(newRoot, nil, 3); err != nil || same != newRoot {
+		t.Errorf("Empty key set should be a no-op, got %v, %v", same, err)
+	}
+}
@@ -0,0 +1,117 @@
+package monaddb
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+// BenchmarkResult reports the throughput and latency an internal benchmark
+// measured. Latencies are in milliseconds.
+type BenchmarkResult struct {
+	ReadsPerSecond  float64
+	WritesPerSecond float64
+	P50ReadLatency  float64
+	P99ReadLatency  float64
+}
+
+// benchmarkSampleKeys caps how many existing keys the benchmark cycles
+// through as its read workload.
+const benchmarkSampleKeys = 256
+
+// BenchmarkSelf runs a read/write benchmark against the live database for
+// the given duration, answering "how fast is this database on this
+// hardware right now". Reads cycle over a sample of the existing keys;
+// writes re-put those same key-value pairs into a scratch version above
+// the current latest, so the logical state is unchanged and history
+// retention ages the scratch version out. An empty database is seeded
+// with a small synthetic data set first. The context can cut the run
+// short; the measurements taken so far are still returned.
+func (db *DB) BenchmarkSelf(ctx context.Context, duration time.Duration) (*BenchmarkResult, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if duration <= 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	version := db.LatestVersion()
+	root, err := db.LoadRoot(version)
+	if err != nil && err != ErrNotFound && err != ErrVersionOutOfRange {
+		return nil, err
+	}
+
+	// Sample the existing data as the workload
+	var workload []Update
+	if root != nil {
+		err = db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+			workload = append(workload, Update{
+				Key:   append([]byte(nil), kv.Key...),
+				Value: append([]byte(nil), kv.Value...),
+			})
+			return len(workload) < benchmarkSampleKeys, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(workload) == 0 {
+		// Seed an empty database with synthetic entries
+		for i := 0; i < benchmarkSampleKeys; i++ {
+			var seed [8]byte
+			binary.BigEndian.PutUint64(seed[:], uint64(i))
+			key := db.KeyHash(seed[:])
+			workload = append(workload, Update{Key: key[:], Value: seed[:]})
+		}
+		version++
+		root, err = db.Upsert(nil, workload, version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scratchVersion := version + 1
+	var readLat latencyRecorder
+	var reads, writes uint64
+
+	start := time.Now()
+	for i := 0; time.Since(start) < duration; i++ {
+		select {
+		case <-ctx.Done():
+			duration = time.Since(start)
+		default:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		u := workload[i%len(workload)]
+		if i%8 == 7 {
+			// One write per eight operations, roughly a validator's mix
+			if _, err := db.Upsert(root, []Update{u}, scratchVersion); err != nil {
+				return nil, err
+			}
+			writes++
+			continue
+		}
+
+		readStart := time.Now()
+		if _, err := db.FindFromRoot(root, u.Key, version); err != nil && err != ErrNotFound {
+			return nil, err
+		}
+		readLat.record(time.Since(readStart))
+		reads++
+	}
+
+	elapsed := time.Since(start).Seconds()
+	if elapsed == 0 {
+		return nil, ErrInvalidArgument
+	}
+	hist := readLat.histogram()
+	return &BenchmarkResult{
+		ReadsPerSecond:  float64(reads) / elapsed,
+		WritesPerSecond: float64(writes) / elapsed,
+		P50ReadLatency:  float64(hist.P50) / float64(time.Millisecond),
+		P99ReadLatency:  float64(hist.P99) / float64(time.Millisecond),
+	}, nil
+}
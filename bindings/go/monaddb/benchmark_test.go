@@ -0,0 +1,54 @@
+package monaddb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestBenchmarkSelf tests a short self-benchmark run.
+func TestBenchmarkSelf(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.BenchmarkSelf(context.Background(), 0); err != ErrInvalidArgument {
+		t.Errorf("Zero duration should fail, got %v", err)
+	}
+
+	result, err := db.BenchmarkSelf(context.Background(), 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("BenchmarkSelf failed: %v", err)
+	}
+	if result.ReadsPerSecond <= 0 {
+		t.Errorf("Expected positive read throughput, got %v", result.ReadsPerSecond)
+	}
+	if result.WritesPerSecond <= 0 {
+		t.Errorf("Expected positive write throughput, got %v", result.WritesPerSecond)
+	}
+	if result.P99ReadLatency < result.P50ReadLatency {
+		t.Errorf("P99 (%v) should be at least P50 (%v)",
+			result.P99ReadLatency, result.P50ReadLatency)
+	}
+}
+
+// TestBenchmarkSelfCancel tests that cancellation cuts the run short.
+func TestBenchmarkSelfCancel(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if _, err := db.BenchmarkSelf(ctx, 10*time.Second); err != nil {
+		t.Fatalf("BenchmarkSelf failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Cancelled benchmark should stop early, took %v", elapsed)
+	}
+}
@@ -0,0 +1,311 @@
+package monaddb
+
+/*
+#include <stddef.h>
+
+// libzstd and liblz4 are on the link line (see db.go). Declaring the
+// handful of entry points we use keeps the package buildable without the
+// compression development headers installed.
+size_t ZSTD_compress(void* dst, size_t dstCapacity,
+                     const void* src, size_t srcSize, int level);
+size_t ZSTD_decompress(void* dst, size_t dstCapacity,
+                       const void* src, size_t srcSize);
+size_t ZSTD_compressBound(size_t srcSize);
+unsigned ZSTD_isError(size_t code);
+
+int LZ4_compress_default(const char* src, char* dst, int srcSize, int dstCapacity);
+int LZ4_compressBound(int inputSize);
+int LZ4_decompress_safe(const char* src, char* dst, int compressedSize, int dstCapacity);
+*/
+import "C"
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// Compressor compresses and decompresses value bytes. Compress and
+// Decompress may use dst as scratch space when its capacity suffices and
+// must return the result slice either way. Decompress receives a dst
+// sized to the exact original length, which is stored alongside each
+// compressed value.
+type Compressor interface {
+	Compress(dst, src []byte) ([]byte, error)
+	Decompress(dst, src []byte) ([]byte, error)
+}
+
+// Compressed values are stored with a four-byte header so that reads can
+// tell them apart from values written before compression existed (or with
+// it disabled): three magic bytes plus a method byte.
+var valueMagic = [3]byte{0xBF, 'm', 'z'}
+
+const (
+	codecRaw      byte = 0    // raw value that happened to start with the magic
+	codecZstd     byte = 1    // zstd frame
+	codecLZ4      byte = 2    // lz4 block
+	codecIndirect byte = 4    // 32-byte content hash; value is in the external store
+	codecExternal byte = 0xFF // user-supplied Compressor (see SetCompressor)
+)
+
+const valueHeaderLen = len(valueMagic) + 1
+
+// maxCompressionLevel is the highest zstd level accepted by
+// SetCompressionLevel.
+const maxCompressionLevel = 22
+
+// installedCompressor pairs a Compressor with the method byte written
+// into value headers, so reads know how to reverse it.
+type installedCompressor struct {
+	method byte
+	c      Compressor
+}
+
+// SetCompressor installs a compressor for all new writes. Built-in
+// compressors (ZstdCompressor, LZ4Compressor) are recorded per value, so
+// their output stays readable regardless of what is installed later.
+// Any other Compressor is recorded as an external method: decoding such
+// values requires the same compressor to be installed at read time.
+//
+// Passing nil or NoCompressor disables compression for new writes.
+func (db *DB) SetCompressor(c Compressor) {
+	switch c.(type) {
+	case nil, NoCompressor:
+		db.compressor.Store(nil)
+	case ZstdCompressor:
+		db.compressor.Store(&installedCompressor{method: codecZstd, c: c})
+	case LZ4Compressor:
+		db.compressor.Store(&installedCompressor{method: codecLZ4, c: c})
+	default:
+		db.compressor.Store(&installedCompressor{method: codecExternal, c: c})
+	}
+}
+
+// SetCompressionLevel sets the zstd compression level applied to values
+// on all new writes. Level 0 disables compression (the default); 1 is
+// zstd's fast mode and 19+ trades CPU for very high ratios, up to 22.
+// Values outside [0, 22] are clamped.
+//
+// Existing values are not rewritten: each stored value is self-describing,
+// so reads transparently decompress old and new values alike.
+func (db *DB) SetCompressionLevel(level int) {
+	if level <= 0 {
+		db.SetCompressor(nil)
+		return
+	}
+	if level > maxCompressionLevel {
+		level = maxCompressionLevel
+	}
+	db.SetCompressor(ZstdCompressor{Level: level})
+}
+
+// ZstdCompressor compresses with zstd at the given level (0 = zstd's
+// default level).
+type ZstdCompressor struct {
+	Level int
+}
+
+func (z ZstdCompressor) Compress(dst, src []byte) ([]byte, error) {
+	if len(src) == 0 {
+		return nil, nil
+	}
+	bound := int(C.ZSTD_compressBound(C.size_t(len(src))))
+	if cap(dst) < bound {
+		dst = make([]byte, bound)
+	}
+	dst = dst[:bound]
+	written := C.ZSTD_compress(
+		unsafe.Pointer(&dst[0]), C.size_t(bound),
+		unsafe.Pointer(&src[0]), C.size_t(len(src)), C.int(z.Level),
+	)
+	if C.ZSTD_isError(written) != 0 {
+		return nil, ErrInternal
+	}
+	return dst[:written], nil
+}
+
+func (z ZstdCompressor) Decompress(dst, src []byte) ([]byte, error) {
+	if len(dst) == 0 {
+		return dst, nil
+	}
+	written := C.ZSTD_decompress(
+		unsafe.Pointer(&dst[0]), C.size_t(len(dst)),
+		unsafe.Pointer(&src[0]), C.size_t(len(src)),
+	)
+	if C.ZSTD_isError(written) != 0 || int(written) != len(dst) {
+		return nil, ErrInternal
+	}
+	return dst, nil
+}
+
+// LZ4Compressor compresses with lz4's default block mode, trading ratio
+// for lower latency than zstd.
+type LZ4Compressor struct{}
+
+func (LZ4Compressor) Compress(dst, src []byte) ([]byte, error) {
+	if len(src) == 0 {
+		return nil, nil
+	}
+	bound := int(C.LZ4_compressBound(C.int(len(src))))
+	if bound <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	if cap(dst) < bound {
+		dst = make([]byte, bound)
+	}
+	dst = dst[:bound]
+	written := C.LZ4_compress_default(
+		(*C.char)(unsafe.Pointer(&src[0])), (*C.char)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)), C.int(bound),
+	)
+	if written <= 0 {
+		return nil, ErrInternal
+	}
+	return dst[:written], nil
+}
+
+func (LZ4Compressor) Decompress(dst, src []byte) ([]byte, error) {
+	if len(dst) == 0 {
+		return dst, nil
+	}
+	written := C.LZ4_decompress_safe(
+		(*C.char)(unsafe.Pointer(&src[0])), (*C.char)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)), C.int(len(dst)),
+	)
+	if int(written) != len(dst) {
+		return nil, ErrInternal
+	}
+	return dst, nil
+}
+
+// NoCompressor stores values verbatim; installing it disables compression.
+type NoCompressor struct{}
+
+func (NoCompressor) Compress(dst, src []byte) ([]byte, error)   { return src, nil }
+func (NoCompressor) Decompress(dst, src []byte) ([]byte, error) { return src, nil }
+
+// encodeValue compresses a value for storage. Values that do not shrink
+// are stored raw; raw values that collide with the header magic are
+// escaped so decoding stays unambiguous.
+func encodeValue(value []byte, ic *installedCompressor) []byte {
+	if len(value) == 0 {
+		return value
+	}
+
+	compressed, err := ic.c.Compress(nil, value)
+	// Header + 8-byte original size precede the compressed payload
+	if err == nil && valueHeaderLen+8+len(compressed) < len(value) {
+		frame := make([]byte, valueHeaderLen+8+len(compressed))
+		copy(frame, valueMagic[:])
+		frame[len(valueMagic)] = ic.method
+		binary.BigEndian.PutUint64(frame[valueHeaderLen:], uint64(len(value)))
+		copy(frame[valueHeaderLen+8:], compressed)
+		return frame
+	}
+
+	// Compression did not pay off; store raw, escaping a magic collision
+	if hasValueHeader(value) {
+		escaped := make([]byte, valueHeaderLen+len(value))
+		copy(escaped, valueMagic[:])
+		escaped[len(valueMagic)] = codecRaw
+		copy(escaped[valueHeaderLen:], value)
+		return escaped
+	}
+	return value
+}
+
+// decodeValue reverses encodeValue. Values without the header are
+// returned unchanged, so data written before compression existed (or with
+// it disabled) reads back verbatim.
+func (db *DB) decodeValue(value []byte) ([]byte, error) {
+	if !hasValueHeader(value) {
+		return value, nil
+	}
+
+	method := value[len(valueMagic)]
+	if method == codecRaw {
+		return value[valueHeaderLen:], nil
+	}
+	if method == codecEncrypted {
+		inner, err := db.openValue(value)
+		if err != nil {
+			return nil, err
+		}
+		// The plaintext is itself codec-encoded (possibly compressed)
+		return db.decodeValue(inner)
+	}
+	if method == codecIndirect {
+		if len(value) != valueHeaderLen+32 {
+			return nil, ErrInternal
+		}
+		es := db.external
+		if es == nil {
+			return nil, ErrNotSupported
+		}
+		var hash [32]byte
+		copy(hash[:], value[valueHeaderLen:])
+		stored, err := es.get(hash)
+		if err != nil {
+			return nil, err
+		}
+		// The stored bytes carry their own codec framing
+		return db.decodeValue(stored)
+	}
+	if len(value) < valueHeaderLen+8 {
+		return nil, ErrInternal
+	}
+	size := binary.BigEndian.Uint64(value[valueHeaderLen:])
+	payload := value[valueHeaderLen+8:]
+	dst := make([]byte, size)
+
+	switch method {
+	case codecZstd:
+		return ZstdCompressor{}.Decompress(dst, payload)
+	case codecLZ4:
+		return LZ4Compressor{}.Decompress(dst, payload)
+	case codecExternal:
+		ic := db.compressor.Load()
+		if ic == nil || ic.method != codecExternal {
+			return nil, ErrNotSupported
+		}
+		return ic.c.Decompress(dst, payload)
+	default:
+		return nil, ErrInternal
+	}
+}
+
+func hasValueHeader(value []byte) bool {
+	return len(value) >= valueHeaderLen &&
+		value[0] == valueMagic[0] && value[1] == valueMagic[1] && value[2] == valueMagic[2]
+}
+
+// escapeUpdates escapes raw Put values that collide with the codec magic.
+// Used when encryption is on without a compressor: decryption recursively
+// decodes the plaintext, so a colliding raw value must carry the escape
+// header that encodeValue would otherwise have added.
+func escapeUpdates(updates []Update) []Update {
+	escaped := make([]Update, len(updates))
+	for i, u := range updates {
+		escaped[i] = u
+		if u.Type != UpdatePut || !hasValueHeader(u.Value) {
+			continue
+		}
+		value := make([]byte, valueHeaderLen+len(u.Value))
+		copy(value, valueMagic[:])
+		value[len(valueMagic)] = codecRaw
+		copy(value[valueHeaderLen:], u.Value)
+		escaped[i].Value = value
+	}
+	return escaped
+}
+
+// encodeUpdates returns a copy of updates with Put values compressed.
+// Delete entries pass through untouched.
+func encodeUpdates(updates []Update, ic *installedCompressor) []Update {
+	encoded := make([]Update, len(updates))
+	for i, u := range updates {
+		encoded[i] = u
+		if u.Type == UpdatePut {
+			encoded[i].Value = encodeValue(u.Value, ic)
+		}
+	}
+	return encoded
+}
@@ -0,0 +1,58 @@
+package monaddb
+
+import (
+	"context"
+	"math/rand"
+)
+
+// compactionSampleRate is the fraction of upserts that check the
+// space-amplification trigger; sampling keeps the check off the hot path.
+const compactionSampleRate = 0.01
+
+// compactionConfig holds the armed trigger threshold.
+type compactionConfig struct {
+	threshold float64
+}
+
+// SetCompactionTrigger arms automatic compaction: when the database's
+// space amplification exceeds the given ratio, a Defragment pass starts
+// in a background goroutine. The check runs after upserts, sampled at 1%
+// so writes stay cheap, and at most one compaction runs at a time; writes
+// are not blocked while it works through the trie in batches. A threshold
+// of zero disarms the trigger (the default).
+func (db *DB) SetCompactionTrigger(spaceAmplification float64) {
+	if spaceAmplification <= 0 {
+		db.compaction.Store(nil)
+		return
+	}
+	db.compaction.Store(&compactionConfig{threshold: spaceAmplification})
+}
+
+// noteCompactionCheck is the sampled post-upsert hook.
+func (db *DB) noteCompactionCheck() {
+	cfg := db.compaction.Load()
+	if cfg == nil || rand.Float64() >= compactionSampleRate {
+		return
+	}
+	db.startCompaction(cfg.threshold)
+}
+
+// startCompaction measures amplification and, above the threshold, runs
+// one Defragment pass in the background. A pass already in flight wins.
+func (db *DB) startCompaction(threshold float64) {
+	if !db.compactRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer db.compactRunning.Store(false)
+		ratio, err := db.SpaceAmplification()
+		if err != nil || ratio <= threshold {
+			return
+		}
+		if err := db.Defragment(context.Background()); err != nil {
+			db.log().Error("automatic compaction failed", "error", err)
+			return
+		}
+		db.compactRuns.Add(1)
+	}()
+}
@@ -0,0 +1,55 @@
+package monaddb
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCompactionTrigger tests that crossing the threshold compacts and
+// staying under it does not.
+func TestCompactionTrigger(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/compact.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	// A few versions of churn leave the footprint well above the live data
+	var root *Node
+	for v := uint64(1); v <= 5; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), make([]byte, 500), v)
+		if err != nil {
+			t.Fatalf("Put at version %d failed: %v", v, err)
+		}
+	}
+
+	// An absurdly high threshold never fires
+	db.startCompaction(1e9)
+	waitForCompaction(db)
+	if db.compactRuns.Load() != 0 {
+		t.Error("Compaction should not run below the threshold")
+	}
+
+	db.startCompaction(1.0)
+	waitForCompaction(db)
+	if db.compactRuns.Load() != 1 {
+		t.Error("Compaction should run above the threshold")
+	}
+
+	// The armed trigger is consulted by the sampled upsert hook
+	db.SetCompactionTrigger(1.0)
+	if db.compaction.Load() == nil {
+		t.Error("Trigger should be armed")
+	}
+	db.SetCompactionTrigger(0)
+	if db.compaction.Load() != nil {
+		t.Error("Zero threshold should disarm the trigger")
+	}
+}
+
+// waitForCompaction blocks until the background pass settles.
+func waitForCompaction(db *DB) {
+	for db.compactRunning.Load() {
+		time.Sleep(time.Millisecond)
+	}
+}
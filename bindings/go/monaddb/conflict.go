@@ -0,0 +1,57 @@
+package monaddb
+
+import "sync"
+
+// conflictTracker remembers which keys each version has written so far,
+// so overlapping writes within one version can be rejected.
+type conflictTracker struct {
+	mu      sync.Mutex
+	enabled bool
+	written map[uint64]map[string]struct{}
+}
+
+// SetWriteConflictDetection toggles per-version write tracking. While
+// enabled, Upsert records every key written to a version; a later Upsert
+// targeting the same version fails with ErrVersionConflict if any of its
+// keys was already written there, leaving the trie untouched.
+//
+// This is the building block for optimistic concurrency: workers write
+// disjoint key sets speculatively and the first overlap aborts instead of
+// silently clobbering. Disabling clears all recorded state.
+func (db *DB) SetWriteConflictDetection(enabled bool) {
+	db.conflicts.mu.Lock()
+	db.conflicts.enabled = enabled
+	if !enabled {
+		db.conflicts.written = nil
+	} else if db.conflicts.written == nil {
+		db.conflicts.written = make(map[uint64]map[string]struct{})
+	}
+	db.conflicts.mu.Unlock()
+}
+
+// checkWrites validates a batch against the recorded writes for version
+// and records its keys. Returns ErrVersionConflict on the first overlap;
+// in that case nothing from the batch is recorded.
+func (c *conflictTracker) checkWrites(version uint64, updates []Update) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return nil
+	}
+
+	set := c.written[version]
+	for i := range updates {
+		if _, clash := set[string(updates[i].Key)]; clash {
+			return ErrVersionConflict
+		}
+	}
+
+	if set == nil {
+		set = make(map[string]struct{}, len(updates))
+		c.written[version] = set
+	}
+	for i := range updates {
+		set[string(updates[i].Key)] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package monaddb
+
+import "testing"
+
+// TestWriteConflictDetection tests overlap rejection within a version.
+func TestWriteConflictDetection(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	db.SetWriteConflictDetection(true)
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("first"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// Disjoint keys in the same version are fine
+	root, err = db.Put(root, makeKey32(0x02), []byte("second"), 1)
+	if err != nil {
+		t.Fatalf("Disjoint Put failed: %v", err)
+	}
+
+	// Rewriting an already-written key in the same version conflicts
+	if _, err := db.Put(root, makeKey32(0x01), []byte("again"), 1); err != ErrVersionConflict {
+		t.Errorf("Overlapping write should return ErrVersionConflict, got %v", err)
+	}
+
+	// The same key in a different version is fine
+	if _, err := db.Put(root, makeKey32(0x01), []byte("v2"), 2); err != nil {
+		t.Errorf("Write at a different version should succeed: %v", err)
+	}
+
+	// Disabling clears the recorded state
+	db.SetWriteConflictDetection(false)
+	if _, err := db.Put(root, makeKey32(0x01), []byte("again"), 1); err != nil {
+		t.Errorf("Write after disabling detection should succeed: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package monaddb
+
+import "encoding/binary"
+
+// readCounter decodes the big-endian uint64 counter stored under key.
+// Absent keys read as zero; a value of any other width is not a counter.
+func (db *DB) readCounter(root *Node, key []byte, version uint64) (uint64, error) {
+	if root == nil {
+		return 0, nil // Empty trie: every counter is zero
+	}
+	node, err := db.FindFromRoot(root, key, version)
+	if err != nil {
+		return 0, err
+	}
+	if node == nil {
+		return 0, nil
+	}
+	value, err := node.Value()
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, ErrInvalidArgument
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// writeCounter stores the counter back as a big-endian uint64.
+func (db *DB) writeCounter(root *Node, key []byte, value, version uint64) (*Node, error) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], value)
+	return db.Put(root, key, buf[:], version)
+}
+
+// Increment adds delta to the big-endian uint64 counter stored under key,
+// treating an absent key as zero, and returns the new root together with
+// the new counter value. Writes are single-threaded by contract, so the
+// read-add-write sequence is atomic. A stored value that is not exactly
+// 8 bytes is rejected with ErrInvalidArgument.
+func (db *DB) Increment(root *Node, key []byte, delta uint64, version uint64) (*Node, uint64, error) {
+	if db == nil || db.ptr == nil {
+		return nil, 0, ErrNullPointer
+	}
+
+	old, err := db.readCounter(root, key, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	value := old + delta
+	newRoot, err := db.writeCounter(root, key, value, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	return newRoot, value, nil
+}
+
+// Decrement subtracts delta from the counter stored under key. When the
+// result would drop below zero it returns ErrUnderflow, unless the
+// database was opened with Options.AllowNegativeCounter, in which case
+// the counter wraps and reads back as a two's-complement negative.
+func (db *DB) Decrement(root *Node, key []byte, delta uint64, version uint64) (*Node, uint64, error) {
+	if db == nil || db.ptr == nil {
+		return nil, 0, ErrNullPointer
+	}
+
+	old, err := db.readCounter(root, key, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	if delta > old && !db.allowNegativeCounter {
+		return nil, 0, ErrUnderflow
+	}
+	value := old - delta
+	newRoot, err := db.writeCounter(root, key, value, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	return newRoot, value, nil
+}
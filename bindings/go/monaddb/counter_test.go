@@ -0,0 +1,63 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestIncrementDecrement tests the uint64 counter helpers.
+func TestIncrementDecrement(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	// Absent keys count from zero
+	root, value, err := db.Increment(nil, makeKey32(0x01), 5, 1)
+	if err != nil || value != 5 {
+		t.Fatalf("Increment failed: got %d, %v", value, err)
+	}
+	root, value, err = db.Increment(root, makeKey32(0x01), 10, 2)
+	if err != nil || value != 15 {
+		t.Fatalf("Increment failed: got %d, %v", value, err)
+	}
+
+	root, value, err = db.Decrement(root, makeKey32(0x01), 6, 3)
+	if err != nil || value != 9 {
+		t.Fatalf("Decrement failed: got %d, %v", value, err)
+	}
+
+	// Going below zero is an underflow by default
+	if _, _, err := db.Decrement(root, makeKey32(0x01), 10, 4); err != ErrUnderflow {
+		t.Errorf("Expected ErrUnderflow, got %v", err)
+	}
+
+	// Non-counter values are rejected
+	root, err = db.Put(root, makeKey32(0x02), []byte("text"), 5)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, _, err := db.Increment(root, makeKey32(0x02), 1, 6); err != ErrInvalidArgument {
+		t.Errorf("A non-8-byte value should be rejected, got %v", err)
+	}
+}
+
+// TestDecrementAllowNegative tests the wrapping mode.
+func TestDecrementAllowNegative(t *testing.T) {
+	db, err := OpenDiskWithOptions(t.TempDir()+"/counter.db", Options{
+		Create:               true,
+		AllowNegativeCounter: true,
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	_, value, err := db.Decrement(nil, makeKey32(0x01), 3, 1)
+	if err != nil {
+		t.Fatalf("Decrement failed: %v", err)
+	}
+	if int64(value) != -3 {
+		t.Errorf("Expected two's-complement -3, got %d", int64(value))
+	}
+}
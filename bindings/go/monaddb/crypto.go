@@ -0,0 +1,175 @@
+package monaddb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"sync"
+)
+
+// codecEncrypted marks a value stored as an AES-256-GCM frame:
+// header, 12-byte nonce, ciphertext. See valueMagic in codec.go.
+const codecEncrypted byte = 3
+
+// keyring holds every AEAD this process has been given, newest last.
+// Reads try them all, so values written under a previous key (or before
+// ClearEncryption) stay readable.
+type keyring struct {
+	mu    sync.Mutex
+	aeads []cipher.AEAD
+}
+
+func (k *keyring) add(a cipher.AEAD) {
+	k.mu.Lock()
+	k.aeads = append(k.aeads, a)
+	k.mu.Unlock()
+}
+
+func (k *keyring) open(nonce, ciphertext []byte) ([]byte, error) {
+	k.mu.Lock()
+	aeads := make([]cipher.AEAD, len(k.aeads))
+	copy(aeads, k.aeads)
+	k.mu.Unlock()
+
+	// Try newest key first; re-encrypted databases hit it immediately
+	for i := len(aeads) - 1; i >= 0; i-- {
+		if plain, err := aeads[i].Open(nil, nonce, ciphertext, nil); err == nil {
+			return plain, nil
+		}
+	}
+	return nil, ErrNotSupported
+}
+
+func newAEAD(key [32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SetEncryption enables AES-256-GCM encryption of all values on new
+// writes. Each value is sealed with a fresh random nonce stored alongside
+// the ciphertext. Encryption layers over compression: a value is
+// compressed first (if a compressor is installed), then encrypted.
+//
+// The key is added to the database's keyring, so values written under any
+// previously set key remain readable.
+func (db *DB) SetEncryption(key [32]byte) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	db.keys.add(aead)
+	db.encrypt.Store(&aead)
+	return nil
+}
+
+// ClearEncryption disables encryption for new writes. Existing encrypted
+// values remain readable through the keyring.
+func (db *DB) ClearEncryption() error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	db.encrypt.Store(nil)
+	return nil
+}
+
+// ReEncrypt rewrites every value of the latest version under a new key.
+// The old keys stay in the keyring for the duration of the pass (and
+// after, in case other versions still hold old ciphertexts).
+//
+// Note that re-encrypting changes the stored bytes, so the state root of
+// the rewritten version changes as well.
+func (db *DB) ReEncrypt(newKey [32]byte) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	if err := db.SetEncryption(newKey); err != nil {
+		return err
+	}
+
+	version := db.LatestVersion()
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return nil
+	}
+
+	newRoot := root
+	batch := make([]Update, 0, defragBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		r, err := db.Upsert(newRoot, batch, version)
+		if err != nil {
+			return err
+		}
+		newRoot = r
+		batch = batch[:0]
+		return nil
+	}
+
+	err = db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		batch = append(batch, Update{Type: UpdatePut, Key: kv.Key, Value: kv.Value})
+		if len(batch) < defragBatchSize {
+			return true, nil
+		}
+		return true, flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// sealValue encrypts an already codec-encoded value into an encrypted
+// frame.
+func sealValue(value []byte, aead cipher.AEAD) []byte {
+	if len(value) == 0 {
+		return value
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		// The system entropy source failing is not recoverable here;
+		// storing plaintext silently would be worse than stopping
+		panic("monaddb: cannot read random nonce: " + err.Error())
+	}
+
+	frame := make([]byte, valueHeaderLen+len(nonce), valueHeaderLen+len(nonce)+len(value)+aead.Overhead())
+	copy(frame, valueMagic[:])
+	frame[len(valueMagic)] = codecEncrypted
+	copy(frame[valueHeaderLen:], nonce)
+	return aead.Seal(frame, nonce, value, nil)
+}
+
+// encryptUpdates returns a copy of updates with Put values sealed.
+// Delete entries pass through untouched.
+func encryptUpdates(updates []Update, aead cipher.AEAD) []Update {
+	encrypted := make([]Update, len(updates))
+	for i, u := range updates {
+		encrypted[i] = u
+		if u.Type != UpdatePut {
+			continue
+		}
+		encrypted[i].Value = sealValue(u.Value, aead)
+	}
+	return encrypted
+}
+
+// openValue decrypts an encrypted frame and returns the inner
+// codec-encoded value.
+func (db *DB) openValue(value []byte) ([]byte, error) {
+	payload := value[valueHeaderLen:]
+	// 12-byte GCM nonce precedes the ciphertext
+	if len(payload) < 12 {
+		return nil, ErrInternal
+	}
+	return db.keys.open(payload[:12], payload[12:])
+}
@@ -0,0 +1,126 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEncryptionRoundTrip tests encrypted writes and keyring reads.
+func TestEncryptionRoundTrip(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var key [32]byte
+	copy(key[:], "0123456789abcdef0123456789abcdef")
+	if err := db.SetEncryption(key); err != nil {
+		t.Fatalf("SetEncryption failed: %v", err)
+	}
+
+	k := makeKey32(0x01)
+	value := []byte("secret account state")
+	root, err := db.Put(nil, k, value, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, k, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	got, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Errorf("Encrypted value did not round-trip: got %q", got)
+	}
+
+	// ClearEncryption stops encrypting new writes but keeps old values
+	// readable through the keyring
+	if err := db.ClearEncryption(); err != nil {
+		t.Fatalf("ClearEncryption failed: %v", err)
+	}
+	got, err = node.Value()
+	if err != nil {
+		t.Fatalf("Value after ClearEncryption failed: %v", err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Error("Encrypted value should stay readable after ClearEncryption")
+	}
+}
+
+// TestEncryptionWithCompression tests the compress-then-encrypt layering.
+func TestEncryptionWithCompression(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	db.SetCompressionLevel(3)
+	var key [32]byte
+	key[0] = 0x42
+	if err := db.SetEncryption(key); err != nil {
+		t.Fatalf("SetEncryption failed: %v", err)
+	}
+
+	value := bytes.Repeat([]byte("compress then encrypt "), 50)
+	k := makeKey32(0x01)
+	root, err := db.Put(nil, k, value, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, k, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	got, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Error("Compressed and encrypted value did not round-trip")
+	}
+}
+
+// TestReEncrypt tests rotating the encryption key over existing data.
+func TestReEncrypt(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/enc.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var oldKey, newKey [32]byte
+	oldKey[0] = 0x01
+	newKey[0] = 0x02
+	if err := db.SetEncryption(oldKey); err != nil {
+		t.Fatalf("SetEncryption failed: %v", err)
+	}
+
+	k := makeKey32(0x01)
+	value := []byte("rotate me")
+	if _, err := db.Put(nil, k, value, 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if err := db.ReEncrypt(newKey); err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+
+	node, err := db.Find(k, db.LatestVersion())
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	got, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Errorf("Re-encrypted value did not round-trip: got %q", got)
+	}
+}
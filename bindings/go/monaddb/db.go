@@ -9,7 +9,7 @@ package monaddb
 #cgo LDFLAGS: -lnomad_mpt -lmonad_ffi
 #cgo LDFLAGS: -lquill -lblake3 -lkeccak
 #cgo LDFLAGS: -Wl,--end-group
-#cgo LDFLAGS: -lstdc++ -lm -luring -lgmp -lcrypto -lzstd -larchive
+#cgo LDFLAGS: -lstdc++ -lm -luring -lgmp -lcrypto -lzstd -llz4 -larchive
 #cgo LDFLAGS: -lboost_fiber -lboost_context -lboost_stacktrace_backtrace -lbacktrace
 
 #include "nomad_mpt.h"
@@ -17,7 +17,12 @@ package monaddb
 */
 import "C"
 import (
+	"crypto/cipher"
+	"fmt"
 	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -25,6 +30,132 @@ import (
 // It is safe for concurrent reads but NOT safe for concurrent writes.
 type DB struct {
 	ptr *C.NomadDb
+
+	// Database directory path; empty for in-memory databases
+	path string
+
+	// Secondary stores for tiered storage (see OpenDiskWithOptions)
+	tiers *tierSet
+
+	// Optional sliding-window read-frequency tracker (see EnableKeyFrequency)
+	keyFreq atomic.Pointer[keyFreqTracker]
+
+	// Cumulative per-key access counters (see HotKeys)
+	hotKeys hotKeyCounter
+
+	// Recently touched node handles held live (see GetAndTouch)
+	touched touchCache
+
+	// Freezes touch-cache order for read-heavy workloads (see Options.ReadMostly)
+	readMostly bool
+
+	// Lets Decrement wrap below zero (see Options.AllowNegativeCounter)
+	allowNegativeCounter bool
+
+	// Cap on AppendToValue results (see Options.MaxAppendedValueSize)
+	maxAppendedValueSize int
+
+	// Cumulative operation counters (see Stats)
+	stats statsCounters
+
+	// Per-second counter ring for windowed rates (see RollingStats)
+	rolling rollingCounters
+
+	// Count of operations currently executing (see QuiesceStats)
+	inflight atomic.Int64
+
+	// Cumulative value bytes submitted for writing (see TotalBytesWritten)
+	logicalWritten atomic.Uint64
+
+	// Armed failure simulations for chaos testing (see InjectFault)
+	faults atomic.Pointer[faultPlan]
+
+	// Active operation trace, nil when not tracing (see StartTrace)
+	trace atomic.Pointer[traceRecorder]
+
+	// Slow-operation JSON log, nil when disabled (see SetSlowLog)
+	slowLog atomic.Pointer[slowLogConfig]
+
+	// Latency histograms for reads and writes (see ReadLatency)
+	readLat  latencyRecorder
+	writeLat latencyRecorder
+
+	// Optional slow-operation reporting (see SetSlowOpThreshold)
+	slowOp atomic.Pointer[slowOpConfig]
+
+	// Async write pipeline depth (see WriteLag)
+	pendingOps   atomic.Uint64
+	pendingBytes atomic.Uint64
+
+	// Optional transparent write batching (see SetAutoBatch)
+	autoBatch atomic.Pointer[autoBatcher]
+
+	// Value compressor for new writes, nil = off (see SetCompressor)
+	compressor atomic.Pointer[installedCompressor]
+
+	// Value encryption for new writes plus the keyring for reads
+	// (see SetEncryption)
+	encrypt atomic.Pointer[cipher.AEAD]
+	keys    keyring
+
+	// Large values kept outside the trie (see Options.LargeValueThreshold)
+	external *externalStore
+
+	// Recorded access times per version (see TouchVersion)
+	versionTimes versionClock
+
+	// Commit metadata per version (see UpsertWithMetadata)
+	versionMeta versionMetaStore
+
+	// Automatic garbage collection schedule (see SetGCInterval)
+	gc gcScheduler
+
+	// Armed compaction trigger, nil when disabled (see SetCompactionTrigger)
+	compaction     atomic.Pointer[compactionConfig]
+	compactRunning atomic.Bool
+	compactRuns    atomic.Uint64
+
+	// Ring of recent puts (see Options.WriteLogSize and GetLatestN)
+	writeLog atomic.Pointer[writeRing]
+
+	// Tamper-detection sentinels (see WriteSentinel)
+	sentinels sentinelStore
+
+	// Optional bump allocator for Upsert marshalling (see SetWriteArena).
+	// Not guarded: writes are single-threaded by contract.
+	writeArena *WriteArena
+
+	// Reference-counted version pins (see LockVersion)
+	versionLocks versionLockTable
+
+	// Per-version written-key tracking (see SetWriteConflictDetection)
+	conflicts conflictTracker
+
+	// Prune notification state (see SetPruneCallback)
+	pruned pruneTracker
+
+	// Diagnostic logger (see SetLogger); nil means NopLogger
+	logger atomic.Pointer[Logger]
+
+	// Observability sinks (see WithMetrics, WithTracer)
+	metrics atomic.Pointer[Metrics]
+	tracer  atomic.Pointer[Tracer]
+
+	// Chunked disk reservation state (see SetPreallocSize)
+	prealloc atomic.Pointer[preallocState]
+
+	// I/O priority bookkeeping (see SetIOPriority); gcIOPrio comes from
+	// Options.GCIOPriority and overrides the idle default for maintenance
+	ioPrio   atomic.Int32
+	gcIOPrio IOPriority
+
+	// Worker pool size and the FIFOs it applies to (see SetNumWorkers)
+	workers atomic.Int32
+	fifoMu  sync.Mutex
+	fifos   []*Fifo
+
+	// Write suspension gate (see PauseWrites)
+	writes writeGate
 }
 
 // OpenMemory opens an in-memory database.
@@ -56,20 +187,67 @@ func OpenDisk(path string, create bool, historyLength uint64) (*DB, error) {
 	if err := codeToError(int(code)); err != nil {
 		return nil, err
 	}
-	
-	db := &DB{ptr: ptr}
+
+	db := &DB{ptr: ptr, path: path}
 	runtime.SetFinalizer(db, (*DB).Close)
 	return db, nil
 }
 
-// Close closes the database and releases all resources.
-func (db *DB) Close() error {
+// Close closes the database and releases all resources, including any
+// secondary tier stores.
+//
+// The first return value reports buffered writes that were discarded
+// because they had not reached the trie yet — nonzero only when an active
+// auto-batcher still held queued writes. Callers that care should log the
+// count and decide whether to reopen and replay; callers that flushed (or
+// never enabled auto-batching) can keep using plain `defer db.Close()`.
+func (db *DB) Close() (pendingWritesLost uint64, err error) {
+	if ab := db.autoBatch.Swap(nil); ab != nil {
+		pendingWritesLost = ab.abandon()
+	}
+	db.stopGC()
 	if db.ptr != nil {
+		db.log().Info("database closed", "path", db.path, "pendingWritesLost", pendingWritesLost)
 		C.nomad_db_close(db.ptr)
 		db.ptr = nil
 		runtime.SetFinalizer(db, nil)
 	}
-	return nil
+	if db.tiers != nil {
+		db.tiers.warm.Close()
+		db.tiers.cold.Close()
+		db.tiers = nil
+	}
+	return pendingWritesLost, nil
+}
+
+// Clone returns an independent in-memory snapshot of the database, cheap
+// enough to take per test case. Existing nodes are shared with the
+// original through reference counting; copy-on-write upserts mean writes
+// on either side create fresh nodes, so neither observes the other's
+// subsequent changes. Codec settings (compressor, encryption, external
+// value store) carry over so values written before the clone decode
+// identically on both sides. Disk databases return ErrNotSupported.
+func (db *DB) Clone() (*DB, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var ptr *C.NomadDb
+	code := C.nomad_db_clone(db.ptr, &ptr)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+
+	clone := &DB{ptr: ptr}
+	if ic := db.compressor.Load(); ic != nil {
+		clone.compressor.Store(ic)
+	}
+	if aead := db.encrypt.Load(); aead != nil {
+		clone.encrypt.Store(aead)
+	}
+	clone.external = db.external
+	runtime.SetFinalizer(clone, (*DB).Close)
+	return clone, nil
 }
 
 // IsOnDisk returns true if the database is using disk storage.
@@ -125,27 +303,46 @@ func (db *DB) VersionIsValid(version uint64) bool {
 //
 // Note: This function only works in disk mode. For in-memory databases,
 // use FindFromRoot() instead.
-func (db *DB) Find(key []byte, version uint64) (*Node, error) {
+func (db *DB) Find(key []byte, version uint64) (node *Node, err error) {
 	if db.ptr == nil {
 		return nil, ErrNullPointer
 	}
-	
+
+	db.inflight.Add(1)
+	defer db.inflight.Add(-1)
+	if tr := db.trace.Load(); tr != nil {
+		defer func() { tr.recordFind(key, version, traceErrStatus(err)) }()
+	}
+	if db.faultFires(FaultIOError) {
+		return nil, ErrIO
+	}
+	db.recordRead(key)
+	defer db.timeRead("find", key, version, time.Now())
+	defer db.span("find")()
+
 	var keyPtr *C.uint8_t
 	if len(key) > 0 {
 		keyPtr = (*C.uint8_t)(unsafe.Pointer(&key[0]))
 	}
-	
+
 	var nodePtr *C.NomadNode
 	code := C.nomad_db_find(db.ptr, keyPtr, C.size_t(len(key)), C.uint64_t(version), &nodePtr)
 	if err := codeToError(int(code)); err != nil {
+		// Versions that aged out of the hot window may live a tier down
+		if db.tiers != nil && (err == ErrNotFound || err == ErrVersionOutOfRange) {
+			return db.tiers.find(key, version)
+		}
 		return nil, err
 	}
-	
+
 	if nodePtr == nil {
+		if db.tiers != nil {
+			return db.tiers.find(key, version)
+		}
 		return nil, nil // Not found
 	}
-	
-	node := &Node{ptr: nodePtr}
+
+	node = &Node{ptr: nodePtr, db: db}
 	runtime.SetFinalizer(node, (*Node).Free)
 	return node, nil
 }
@@ -159,19 +356,31 @@ func (db *DB) Find(key []byte, version uint64) (*Node, error) {
 //   - version: Version number
 //
 // Returns nil if the key is not found.
-func (db *DB) FindFromRoot(root *Node, key []byte, version uint64) (*Node, error) {
+func (db *DB) FindFromRoot(root *Node, key []byte, version uint64) (node *Node, err error) {
 	if db.ptr == nil {
 		return nil, ErrNullPointer
 	}
 	if root == nil || root.ptr == nil {
 		return nil, ErrNullPointer
 	}
-	
+
+	db.inflight.Add(1)
+	defer db.inflight.Add(-1)
+	if tr := db.trace.Load(); tr != nil {
+		defer func() { tr.recordFind(key, version, traceErrStatus(err)) }()
+	}
+	if db.faultFires(FaultIOError) {
+		return nil, ErrIO
+	}
+	db.recordRead(key)
+	defer db.timeRead("find_from_root", key, version, time.Now())
+	defer db.span("find_from_root")()
+
 	var keyPtr *C.uint8_t
 	if len(key) > 0 {
 		keyPtr = (*C.uint8_t)(unsafe.Pointer(&key[0]))
 	}
-	
+
 	var nodePtr *C.NomadNode
 	code := C.nomad_db_find_from_root(db.ptr, root.ptr, keyPtr, C.size_t(len(key)), C.uint64_t(version), &nodePtr)
 	if err := codeToError(int(code)); err != nil {
@@ -182,11 +391,49 @@ func (db *DB) FindFromRoot(root *Node, key []byte, version uint64) (*Node, error
 		return nil, nil // Not found
 	}
 	
-	node := &Node{ptr: nodePtr}
+	node = &Node{ptr: nodePtr, db: db}
 	runtime.SetFinalizer(node, (*Node).Free)
 	return node, nil
 }
 
+// FindAll looks up a single key at multiple versions in one call.
+//
+// roots maps each version number to the root node for that version
+// (typically obtained from Upsert or LoadRoot). The returned map contains
+// an entry for every version where the key was found; versions where the
+// key is absent are simply missing from the result.
+//
+// This amortizes lookup startup costs when serving the same key across
+// many block versions (e.g. eth_getStorageAt over a version range).
+func (db *DB) FindAll(roots map[uint64]*Node, key []byte) (map[uint64][]byte, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	result := make(map[uint64][]byte, len(roots))
+	for version, root := range roots {
+		if root == nil || root.ptr == nil {
+			continue
+		}
+
+		node, err := db.FindFromRoot(root, key, version)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil || !node.HasValue() {
+			continue
+		}
+
+		value, err := node.Value()
+		if err != nil {
+			return nil, err
+		}
+		result[version] = value
+	}
+
+	return result, nil
+}
+
 // LoadRoot loads the root node for a specific version.
 func (db *DB) LoadRoot(version uint64) (*Node, error) {
 	if db.ptr == nil {
@@ -203,11 +450,86 @@ func (db *DB) LoadRoot(version uint64) (*Node, error) {
 		return nil, nil
 	}
 	
-	node := &Node{ptr: nodePtr}
+	node := &Node{ptr: nodePtr, db: db}
+	runtime.SetFinalizer(node, (*Node).Free)
+	return node, nil
+}
+
+// LatestRoot loads the root node of the latest version. Equivalent to
+// db.LoadRoot(db.LatestVersion()) but performed as one C call, so a
+// commit cannot land between reading the version and loading its root.
+func (db *DB) LatestRoot() (*Node, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var nodePtr *C.NomadNode
+	code := C.nomad_db_latest_root(db.ptr, nil, &nodePtr)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+
+	if nodePtr == nil {
+		return nil, nil
+	}
+
+	node := &Node{ptr: nodePtr, db: db}
 	runtime.SetFinalizer(node, (*Node).Free)
 	return node, nil
 }
 
+// LoadVersionBatch loads the roots for a range of versions in a single C
+// call, sparing sync protocols one FFI round trip per version. The result
+// has one entry per requested version, nil where that version has no root.
+// On failure the error reports how many versions loaded before the batch
+// stopped; no node handles are retained from a failed batch.
+func (db *DB) LoadVersionBatch(versions []uint64) ([]*Node, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	ptrs := make([]*C.NomadNode, len(versions))
+	var loaded C.size_t
+	code := C.nomad_db_load_root_batch(db.ptr,
+		(*C.uint64_t)(unsafe.Pointer(&versions[0])), C.size_t(len(versions)),
+		(**C.NomadNode)(unsafe.Pointer(&ptrs[0])), &loaded)
+	if err := codeToError(int(code)); err != nil {
+		return nil, fmt.Errorf("batch stopped after %d of %d versions: %w",
+			int(loaded), len(versions), err)
+	}
+
+	roots := make([]*Node, len(versions))
+	for i, ptr := range ptrs {
+		if ptr == nil {
+			continue
+		}
+		node := &Node{ptr: ptr, db: db}
+		runtime.SetFinalizer(node, (*Node).Free)
+		roots[i] = node
+	}
+	return roots, nil
+}
+
+// HistoricalRootHash returns the root hash of a historical version without
+// materializing a Node. The C layer resolves the hash directly, so there is
+// no node handle to track or free — use this when only the hash matters,
+// such as validating a block header against stored state.
+func (db *DB) HistoricalRootHash(version uint64) ([32]byte, error) {
+	var hash [32]byte
+	if db.ptr == nil {
+		return hash, ErrNullPointer
+	}
+
+	code := C.nomad_db_root_hash(db.ptr, C.uint64_t(version), (*C.uint8_t)(unsafe.Pointer(&hash[0])))
+	if err := codeToError(int(code)); err != nil {
+		return hash, err
+	}
+	return hash, nil
+}
+
 // UpdateType represents the type of update operation.
 type UpdateType int
 
@@ -223,107 +545,269 @@ type Update struct {
 	Value []byte // nil for delete
 }
 
-// Upsert applies updates to the database.
-// Returns the new root node.
-func (db *DB) Upsert(root *Node, updates []Update, version uint64) (*Node, error) {
-	if db.ptr == nil {
-		return nil, ErrNullPointer
+// marshaledUpdates holds C-allocated copies of a []Update batch.
+// Call free() after the C call has returned. Buffers served from a
+// WriteArena are not tracked here; the arena reclaims them on Reset.
+type marshaledUpdates struct {
+	ptr      *C.NomadUpdate
+	ptrOwned bool // ptr came from C.malloc rather than an arena
+	bufs     []*C.uint8_t
+}
+
+func (m *marshaledUpdates) free() {
+	for _, p := range m.bufs {
+		if p != nil {
+			C.free(unsafe.Pointer(p))
+		}
 	}
-	
-	if len(updates) == 0 {
-		return root, nil
+	if m.ptr != nil && m.ptrOwned {
+		C.free(unsafe.Pointer(m.ptr))
 	}
-	
-	// 使用 C 分配内存来存储 updates，避免 CGO 指针规则问题
-	// C.malloc 分配的内存不受 Go GC 管理
-	cUpdatesPtr := (*C.NomadUpdate)(C.malloc(C.size_t(len(updates)) * C.size_t(unsafe.Sizeof(C.NomadUpdate{}))))
-	if cUpdatesPtr == nil {
+}
+
+// marshalBuf copies data into the arena when possible, falling back to
+// C.malloc (tracked in m.bufs for free()).
+func (m *marshaledUpdates) marshalBuf(data []byte, arena *WriteArena) (*C.uint8_t, error) {
+	if arena != nil {
+		if p := arena.allocBytes(data); p != nil {
+			return p, nil
+		}
+	}
+	p := (*C.uint8_t)(C.malloc(C.size_t(len(data))))
+	if p == nil {
 		return nil, ErrOutOfMemory
 	}
-	defer C.free(unsafe.Pointer(cUpdatesPtr))
-	
-	// 将 cUpdatesPtr 转换为 slice 以便索引访问
-	cUpdates := unsafe.Slice(cUpdatesPtr, len(updates))
-	
-	// 为 key 和 value 分配 C 内存
-	var cKeys []*C.uint8_t
-	var cValues []*C.uint8_t
-	defer func() {
-		for _, p := range cKeys {
-			if p != nil {
-				C.free(unsafe.Pointer(p))
-			}
-		}
-		for _, p := range cValues {
-			if p != nil {
-				C.free(unsafe.Pointer(p))
-			}
+	m.bufs = append(m.bufs, p)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(p)), len(data)), data)
+	return p, nil
+}
+
+// marshalUpdates copies a batch of updates into C-allocated memory,
+// bump-allocating from arena when one is provided (see SetWriteArena).
+//
+// 使用 C 分配内存来存储 updates，避免 CGO 指针规则问题
+// C.malloc 分配的内存不受 Go GC 管理
+func marshalUpdates(updates []Update, arena *WriteArena) (*marshaledUpdates, error) {
+	m := &marshaledUpdates{}
+
+	arraySize := uintptr(len(updates)) * unsafe.Sizeof(C.NomadUpdate{})
+	if arena != nil {
+		m.ptr = (*C.NomadUpdate)(arena.alloc(arraySize))
+	}
+	if m.ptr == nil {
+		m.ptr = (*C.NomadUpdate)(C.malloc(C.size_t(arraySize)))
+		if m.ptr == nil {
+			return nil, ErrOutOfMemory
 		}
-	}()
-	
+		m.ptrOwned = true
+	}
+
+	// 将 m.ptr 转换为 slice 以便索引访问
+	cUpdates := unsafe.Slice(m.ptr, len(updates))
+
 	for i, u := range updates {
 		cUpdates[i]._type = C.NomadUpdateType(u.Type)
-		
+		cUpdates[i].key = nil
+		cUpdates[i].key_len = 0
+		cUpdates[i].value = nil
+		cUpdates[i].value_len = 0
+
 		if len(u.Key) > 0 {
-			keyPtr := (*C.uint8_t)(C.malloc(C.size_t(len(u.Key))))
-			if keyPtr == nil {
-				return nil, ErrOutOfMemory
+			keyPtr, err := m.marshalBuf(u.Key, arena)
+			if err != nil {
+				m.free()
+				return nil, err
 			}
-			cKeys = append(cKeys, keyPtr)
-			// 复制 key 数据到 C 内存
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(keyPtr)), len(u.Key)), u.Key)
 			cUpdates[i].key = keyPtr
 			cUpdates[i].key_len = C.size_t(len(u.Key))
 		}
-		
+
 		if len(u.Value) > 0 {
-			valuePtr := (*C.uint8_t)(C.malloc(C.size_t(len(u.Value))))
-			if valuePtr == nil {
-				return nil, ErrOutOfMemory
+			valuePtr, err := m.marshalBuf(u.Value, arena)
+			if err != nil {
+				m.free()
+				return nil, err
 			}
-			cValues = append(cValues, valuePtr)
-			// 复制 value 数据到 C 内存
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(valuePtr)), len(u.Value)), u.Value)
 			cUpdates[i].value = valuePtr
 			cUpdates[i].value_len = C.size_t(len(u.Value))
 		}
 	}
-	
+
+	return m, nil
+}
+
+// Upsert applies updates to the database.
+// Returns the new root node.
+func (db *DB) Upsert(root *Node, updates []Update, version uint64) (*Node, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	if len(updates) == 0 {
+		return root, nil
+	}
+
+	return db.applyUpdates(root, updates, version, "upsert", func(rootPtr *C.NomadNode, cUpdates *C.NomadUpdate, count C.size_t, newRootPtr **C.NomadNode) C.NomadError {
+		return C.nomad_db_upsert(db.ptr, rootPtr, cUpdates, count, C.uint64_t(version), newRootPtr)
+	})
+}
+
+// applyUpdates is the write pipeline shared by every batch entry point:
+// inflight accounting, tracing, the write log, fault injection, the pause
+// gate (see PauseWrites), conflict detection, prune notification, write
+// statistics and
+// prealloc accounting, latency timing, the codec stages, marshalling, and
+// tier migration all bracket the engine call performed by call. op labels
+// the latency and span records. A tier migration failure is reported
+// alongside the already-committed new root, since the engine write cannot
+// be rolled back at that point.
+func (db *DB) applyUpdates(root *Node, updates []Update, version uint64, op string, call func(rootPtr *C.NomadNode, cUpdates *C.NomadUpdate, count C.size_t, newRootPtr **C.NomadNode) C.NomadError) (newRoot *Node, err error) {
+	db.inflight.Add(1)
+	defer db.inflight.Add(-1)
+	if tr := db.trace.Load(); tr != nil {
+		// Capture the updates as submitted, before codec encoding
+		orig := updates
+		defer func() { tr.recordUpsert(orig, version, traceErrStatus(err)) }()
+	}
+	if ring := db.writeLog.Load(); ring != nil {
+		// Log the updates as submitted, but only for committed upserts
+		orig := updates
+		defer func() {
+			if err == nil {
+				ring.note(orig, version)
+			}
+		}()
+	}
+	if db.faultFires(FaultIOError) {
+		return nil, ErrIO
+	}
+	if db.faultFires(FaultSlowWrite) {
+		time.Sleep(faultSlowWriteDelay)
+	}
+
+	// Blocks while writes are paused (see PauseWrites)
+	db.writes.flow.RLock()
+	defer db.writes.flow.RUnlock()
+
+	if err := db.conflicts.checkWrites(version, updates); err != nil {
+		return nil, err
+	}
+	db.notifyPrunes(version)
+
+	var writeBytes uint64
+	for i := range updates {
+		db.recordWrite(&updates[i])
+		writeBytes += uint64(len(updates[i].Key) + len(updates[i].Value))
+	}
+	db.notePreallocWrite(writeBytes)
+	defer db.timeWrite(op, updates[0].Key, version, time.Now())
+	defer db.span(op)()
+
+	aead := db.encrypt.Load()
+	if ic := db.compressor.Load(); ic != nil {
+		updates = encodeUpdates(updates, ic)
+	} else if aead != nil || db.external != nil {
+		updates = escapeUpdates(updates)
+	}
+	if db.external != nil {
+		var extErr error
+		updates, extErr = db.externalizeUpdates(updates, db.external)
+		if extErr != nil {
+			return nil, extErr
+		}
+	}
+	if aead != nil {
+		updates = encryptUpdates(updates, *aead)
+	}
+
+	m, err := marshalUpdates(updates, db.writeArena)
+	if err != nil {
+		return nil, err
+	}
+	defer m.free()
+	if db.writeArena != nil {
+		defer db.writeArena.Reset()
+	}
+
 	var rootPtr *C.NomadNode
 	if root != nil {
 		rootPtr = root.ptr
 	}
-	
+
 	var newRootPtr *C.NomadNode
-	code := C.nomad_db_upsert(
-		db.ptr,
-		rootPtr,
-		cUpdatesPtr,
-		C.size_t(len(updates)),
-		C.uint64_t(version),
-		&newRootPtr,
-	)
-	
+	code := call(rootPtr, m.ptr, C.size_t(len(updates)), &newRootPtr)
 	if err := codeToError(int(code)); err != nil {
 		return nil, err
 	}
-	
-	if newRootPtr == nil {
-		return nil, nil
+
+	// Wrap the root before anything that can still fail: past this point
+	// the engine write has committed, and the handle must reach the caller
+	// (or at least a finalizer) either way
+	if newRootPtr != nil {
+		newRoot = &Node{ptr: newRootPtr, db: db}
+		runtime.SetFinalizer(newRoot, (*Node).Free)
 	}
-	
-	newRoot := &Node{ptr: newRootPtr}
-	runtime.SetFinalizer(newRoot, (*Node).Free)
+
+	if db.tiers != nil {
+		if err := db.tiers.migrate(db, db.LatestVersion()); err != nil {
+			// Hand the committed root back alongside the error: hiding it
+			// would make the caller retry a write that already landed, and
+			// migration retries on the next write anyway
+			return newRoot, err
+		}
+	}
+	db.noteCompactionCheck()
 	return newRoot, nil
 }
 
+// UpsertIfVersion applies updates only if the database is still at
+// expectedVersion, targeting newVersion.
+//
+// The version check and the update are atomic at the C level: when several
+// writers race on the same expectedVersion, at most one succeeds and the
+// others receive ErrVersionConflict. This is the building block for
+// optimistic concurrency control where goroutines maintain local state and
+// periodically try to commit.
+//
+// Aside from the version check this runs the same write pipeline as
+// Upsert: it blocks while writes are paused and feeds tracing, the write
+// log, statistics and tier migration the same way.
+func (db *DB) UpsertIfVersion(root *Node, updates []Update, expectedVersion, newVersion uint64) (*Node, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	if len(updates) == 0 {
+		if db.LatestVersion() != expectedVersion {
+			return nil, ErrVersionConflict
+		}
+		return root, nil
+	}
+
+	return db.applyUpdates(root, updates, newVersion, "upsert_if_version", func(rootPtr *C.NomadNode, cUpdates *C.NomadUpdate, count C.size_t, newRootPtr **C.NomadNode) C.NomadError {
+		return C.nomad_db_upsert_if_version(db.ptr, rootPtr, cUpdates, count, C.uint64_t(expectedVersion), C.uint64_t(newVersion), newRootPtr)
+	})
+}
+
 // Put is a convenience method to insert a single key-value pair.
+//
+// With auto-batching enabled (see SetAutoBatch) the write is queued and a
+// nil root is returned immediately.
 func (db *DB) Put(root *Node, key, value []byte, version uint64) (*Node, error) {
+	if b := db.autoBatch.Load(); b != nil {
+		return nil, b.enqueue(root, Update{Type: UpdatePut, Key: key, Value: value}, version)
+	}
 	return db.Upsert(root, []Update{{Type: UpdatePut, Key: key, Value: value}}, version)
 }
 
 // Delete is a convenience method to delete a single key.
+//
+// With auto-batching enabled (see SetAutoBatch) the write is queued and a
+// nil root is returned immediately.
 func (db *DB) Delete(root *Node, key []byte, version uint64) (*Node, error) {
+	if b := db.autoBatch.Load(); b != nil {
+		return nil, b.enqueue(root, Update{Type: UpdateDelete, Key: key}, version)
+	}
 	return db.Upsert(root, []Update{{Type: UpdateDelete, Key: key}}, version)
 }
 
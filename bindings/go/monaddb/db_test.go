@@ -239,6 +239,71 @@ func TestUpsertBatch(t *testing.T) {
 	}
 }
 
+// TestFindAll tests retrieving one key across multiple versions.
+func TestFindAll(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+
+	root1, err := db.Put(nil, key, []byte("v1"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	root2, err := db.Put(root1, key, []byte("v2"), 2)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	// Version 3 does not contain the key
+	root3, err := db.Put(nil, makeKey32(0x02), []byte("other"), 3)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	values, err := db.FindAll(map[uint64]*Node{1: root1, 2: root2, 3: root3}, key)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+
+	if string(values[1]) != "v1" || string(values[2]) != "v2" {
+		t.Errorf("FindAll values mismatch: %v", values)
+	}
+	if _, ok := values[3]; ok {
+		t.Error("FindAll should omit versions where the key is absent")
+	}
+}
+
+// TestUpsertIfVersion tests optimistic version-checked updates.
+func TestUpsertIfVersion(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Type: UpdatePut, Key: makeKey32(0x01), Value: []byte("value1")},
+	}
+
+	// Memory DBs report latest version 0, so expectedVersion=0 must succeed
+	root, err := db.UpsertIfVersion(nil, updates, db.LatestVersion(), 1)
+	if err != nil {
+		t.Fatalf("UpsertIfVersion with matching version failed: %v", err)
+	}
+	if root == nil {
+		t.Fatal("UpsertIfVersion returned nil root")
+	}
+
+	// A stale expected version must be rejected
+	_, err = db.UpsertIfVersion(root, updates, db.LatestVersion()+1, 2)
+	if err != ErrVersionConflict {
+		t.Errorf("UpsertIfVersion with stale version should return ErrVersionConflict, got %v", err)
+	}
+}
+
 // TestNodeHash tests getting node hashes.
 func TestNodeHash(t *testing.T) {
 	db, err := OpenMemory()
@@ -513,3 +578,58 @@ func TestFifoNotImplemented(t *testing.T) {
 		t.Error("FIFO should report as not implemented")
 	}
 }
+
+// TestClone tests that a clone shares existing state but diverges on write.
+func TestClone(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("shared"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	clone, err := db.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	defer clone.Close()
+
+	// The clone reads state written before the split
+	node, err := clone.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("Find on clone failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil || string(value) != "shared" {
+		t.Errorf("Clone should see pre-split value, got %q, %v", value, err)
+	}
+
+	// A write to the clone stays invisible through the original's root
+	cloneRoot, err := clone.Put(root, makeKey32(0x02), []byte("clone-only"), 2)
+	if err != nil {
+		t.Fatalf("Put on clone failed: %v", err)
+	}
+	if _, err := clone.FindFromRoot(cloneRoot, makeKey32(0x02), 2); err != nil {
+		t.Errorf("Clone should see its own write: %v", err)
+	}
+	if node, err := db.FindFromRoot(root, makeKey32(0x02), 1); err != nil || node != nil {
+		t.Errorf("Original should not see the clone's write, got %v, %v", node, err)
+	}
+}
+
+// TestCloneDiskNotSupported tests that disk databases refuse to clone.
+func TestCloneDiskNotSupported(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/clone.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Clone(); err != ErrNotSupported {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package monaddb
+
+import (
+	"bytes"
+	"sort"
+)
+
+// DiffEntry is one key-level change between two tries. OldValue is nil
+// for insertions and NewValue is nil for deletions.
+type DiffEntry struct {
+	Key      []byte
+	OldValue []byte
+	NewValue []byte
+}
+
+// DiffIterator walks the key-level changes between two tries in ascending
+// key order. It also remembers the origin root the diff was computed
+// against, which Rebase uses for conflict detection.
+type DiffIterator struct {
+	db      *DB
+	origin  *Node
+	version uint64
+	entries []DiffEntry
+	pos     int
+}
+
+// Diff computes the key-level changes that turn the trie at from into the
+// trie at to. Both roots are read at the given version; a nil root is an
+// empty trie, so Diff(nil, root, v) lists every key in root as inserted.
+func (db *DB) Diff(from, to *Node, version uint64) (*DiffIterator, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	old := make(map[string][]byte)
+	err := db.forEach(from, nil, version, func(kv kvEntry) (bool, error) {
+		old[string(kv.Key)] = kv.Value
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []DiffEntry
+	err = db.forEach(to, nil, version, func(kv kvEntry) (bool, error) {
+		oldValue, existed := old[string(kv.Key)]
+		if existed {
+			delete(old, string(kv.Key))
+			if bytes.Equal(oldValue, kv.Value) {
+				return true, nil // unchanged
+			}
+		}
+		entries = append(entries, DiffEntry{Key: kv.Key, OldValue: oldValue, NewValue: kv.Value})
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys remaining in old were deleted
+	for key, value := range old {
+		entries = append(entries, DiffEntry{Key: []byte(key), OldValue: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Key, entries[j].Key) < 0
+	})
+
+	return &DiffIterator{db: db, origin: from, version: version, entries: entries}, nil
+}
+
+// Next returns the next change, or false once the diff is exhausted.
+func (it *DiffIterator) Next() (DiffEntry, bool) {
+	if it.pos >= len(it.entries) {
+		return DiffEntry{}, false
+	}
+	entry := it.entries[it.pos]
+	it.pos++
+	return entry, true
+}
+
+// Len returns the total number of changes in the diff.
+func (it *DiffIterator) Len() int {
+	return len(it.entries)
+}
+
+// Reset rewinds the iterator to the first change.
+func (it *DiffIterator) Reset() {
+	it.pos = 0
+}
+
+// Rebase applies the full set of changes in diff on top of newBase,
+// producing a new trie at version. Before applying, each changed key is
+// checked against newBase: if newBase's value for the key differs from
+// the value the diff's origin held, the same key was modified on both
+// sides and Rebase returns ErrConflict. This is the trie-level analogue
+// of replaying a transaction set onto a different parent during a reorg.
+func (db *DB) Rebase(diff *DiffIterator, newBase *Node, version uint64) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if diff == nil {
+		return newBase, nil
+	}
+
+	root := newBase
+	batch := make([]Update, 0, defragBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		r, err := db.Upsert(root, batch, version)
+		if err != nil {
+			return err
+		}
+		root = r
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, entry := range diff.entries {
+		node, err := db.FindFromRoot(newBase, entry.Key, version)
+		if err != nil && err != ErrNotFound {
+			return nil, err
+		}
+		var baseValue []byte
+		if node != nil && node.HasValue() {
+			if baseValue, err = node.Value(); err != nil {
+				return nil, err
+			}
+		}
+		if !bytes.Equal(baseValue, entry.OldValue) {
+			return nil, ErrConflict
+		}
+
+		if entry.NewValue == nil {
+			batch = append(batch, Update{Type: UpdateDelete, Key: entry.Key})
+		} else {
+			batch = append(batch, Update{Type: UpdatePut, Key: entry.Key, Value: entry.NewValue})
+		}
+		if len(batch) >= defragBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
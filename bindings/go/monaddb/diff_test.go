@@ -0,0 +1,93 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestDiff tests change detection between two tries.
+func TestDiff(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	from := buildTrie(t, db, 1, map[byte]string{0x01: "keep", 0x02: "old", 0x03: "drop"})
+	to := buildTrie(t, db, 1, map[byte]string{0x01: "keep", 0x02: "new", 0x04: "add"})
+
+	diff, err := db.Diff(from, to, 1)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff.Len() != 3 {
+		t.Fatalf("Diff should hold 3 changes, got %d", diff.Len())
+	}
+
+	// Entries come out in ascending key order: 0x02, 0x03, 0x04
+	modify, _ := diff.Next()
+	if !bytes.Equal(modify.Key, makeKey32(0x02)) || string(modify.NewValue) != "new" || string(modify.OldValue) != "old" {
+		t.Errorf("First change should modify 0x02 old->new, got %+v", modify)
+	}
+	del, _ := diff.Next()
+	if !bytes.Equal(del.Key, makeKey32(0x03)) || del.NewValue != nil {
+		t.Errorf("Second change should delete 0x03, got %+v", del)
+	}
+	ins, _ := diff.Next()
+	if !bytes.Equal(ins.Key, makeKey32(0x04)) || ins.OldValue != nil {
+		t.Errorf("Third change should insert 0x04, got %+v", ins)
+	}
+	if _, ok := diff.Next(); ok {
+		t.Error("Iterator should be exhausted")
+	}
+
+	diff.Reset()
+	if _, ok := diff.Next(); !ok {
+		t.Error("Reset should rewind the iterator")
+	}
+}
+
+// TestRebase tests replaying a diff onto a different base.
+func TestRebase(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	origin := buildTrie(t, db, 1, map[byte]string{0x01: "a", 0x02: "b"})
+	modified, err := db.Put(origin, makeKey32(0x02), []byte("b2"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	diff, err := db.Diff(origin, modified, 1)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	// A base that only changed unrelated keys rebases cleanly
+	newBase, err := db.Put(origin, makeKey32(0x03), []byte("c"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	rebased, err := db.Rebase(diff, newBase, 1)
+	if err != nil {
+		t.Fatalf("Rebase failed: %v", err)
+	}
+	if got := lookupString(t, db, rebased, 0x02, 1); got != "b2" {
+		t.Errorf("Key 0x02 should be %q after rebase, got %q", "b2", got)
+	}
+	if got := lookupString(t, db, rebased, 0x03, 1); got != "c" {
+		t.Errorf("Key 0x03 should survive the rebase, got %q", got)
+	}
+
+	// A base that touched the same key conflicts
+	conflicting, err := db.Put(origin, makeKey32(0x02), []byte("other"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := db.Rebase(diff, conflicting, 1); err != ErrConflict {
+		t.Errorf("Rebase onto a conflicting base should return ErrConflict, got %v", err)
+	}
+}
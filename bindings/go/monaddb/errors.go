@@ -19,6 +19,7 @@ const (
 	ErrCodeVersionOutOfRange  = 5
 	ErrCodeNotSupported       = 6
 	ErrCodeOutOfMemory        = 7
+	ErrCodeVersionConflict    = 8
 	ErrCodeInternal           = 255
 )
 
@@ -31,9 +32,68 @@ var (
 	ErrVersionOutOfRange = errors.New("monaddb: version out of range")
 	ErrNotSupported      = errors.New("monaddb: operation not supported")
 	ErrOutOfMemory       = errors.New("monaddb: out of memory")
+	ErrVersionConflict   = errors.New("monaddb: version conflict")
 	ErrInternal          = errors.New("monaddb: internal error")
 )
 
+// ErrConflict is returned by a ConflictResolver to abort a trie merge,
+// and by operations that detect conflicting writes to the same key.
+var ErrConflict = errors.New("monaddb: conflicting values for key")
+
+// ErrSentinelMismatch is returned by VerifySentinel when the recorded
+// HMAC no longer matches the version's state.
+var ErrSentinelMismatch = errors.New("monaddb: sentinel mismatch")
+
+// ErrStateMismatch is returned by ValidateStateRoot when the computed root
+// hash differs from the expected one. The concrete error is a
+// *StateMismatchError carrying both hashes; match it with errors.Is
+// against this sentinel.
+var ErrStateMismatch = errors.New("monaddb: state root mismatch")
+
+// StateMismatchError reports a failed state-root validation with the hash
+// consensus expected and the hash the trie actually produced.
+type StateMismatchError struct {
+	Expected [32]byte
+	Actual   [32]byte
+}
+
+func (e *StateMismatchError) Error() string {
+	return fmt.Sprintf("monaddb: state root mismatch: expected %x, got %x", e.Expected, e.Actual)
+}
+
+func (e *StateMismatchError) Unwrap() error { return ErrStateMismatch }
+
+// ErrUnderflow is returned by Decrement when the result would drop below
+// zero and Options.AllowNegativeCounter is off.
+var ErrUnderflow = errors.New("monaddb: counter underflow")
+
+// ErrValueTooLarge is returned by AppendToValue when the combined value
+// would exceed Options.MaxAppendedValueSize.
+var ErrValueTooLarge = errors.New("monaddb: value too large")
+
+// ErrIntegrity is returned by VerifyAll when the database fails its
+// consistency checks. The concrete error is an *IntegrityError locating
+// the damage; match it with errors.Is against this sentinel.
+var ErrIntegrity = errors.New("monaddb: integrity check failed")
+
+// IntegrityError pinpoints a failed integrity check: which version was
+// being verified, the key whose path exposed the damage (nil when the
+// check concerned the version as a whole), and what went wrong.
+type IntegrityError struct {
+	Version uint64
+	Key     []byte
+	Detail  string
+}
+
+func (e *IntegrityError) Error() string {
+	if e.Key == nil {
+		return fmt.Sprintf("monaddb: integrity check failed at version %d: %s", e.Version, e.Detail)
+	}
+	return fmt.Sprintf("monaddb: integrity check failed at version %d, key %x: %s", e.Version, e.Key, e.Detail)
+}
+
+func (e *IntegrityError) Unwrap() error { return ErrIntegrity }
+
 // codeToError converts a C error code to a Go error
 func codeToError(code int) error {
 	switch code {
@@ -53,6 +113,8 @@ func codeToError(code int) error {
 		return ErrNotSupported
 	case ErrCodeOutOfMemory:
 		return ErrOutOfMemory
+	case ErrCodeVersionConflict:
+		return ErrVersionConflict
 	default:
 		return fmt.Errorf("monaddb: unknown error code %d", code)
 	}
@@ -0,0 +1,83 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import "time"
+
+// NodeType is the structural classification of a trie node.
+type NodeType int
+
+const (
+	NodeTypeBranch    NodeType = NodeType(C.NOMAD_NODE_BRANCH)
+	NodeTypeExtension NodeType = NodeType(C.NOMAD_NODE_EXTENSION)
+	NodeTypeLeaf      NodeType = NodeType(C.NOMAD_NODE_LEAF)
+)
+
+// String returns the lowercase name of the node type.
+func (t NodeType) String() string {
+	switch t {
+	case NodeTypeBranch:
+		return "branch"
+	case NodeTypeExtension:
+		return "extension"
+	case NodeTypeLeaf:
+		return "leaf"
+	default:
+		return "unknown"
+	}
+}
+
+// Type classifies the node as branch, extension or leaf.
+func (n *Node) Type() NodeType {
+	if n == nil || n.ptr == nil {
+		return NodeTypeLeaf
+	}
+	return NodeType(C.nomad_node_type(n.ptr))
+}
+
+// ExplainResult describes how a lookup traversed the trie — the query
+// plan a Find would execute.
+type ExplainResult struct {
+	PathLength       int        // nodes visited from root to the deepest one
+	NodeTypesVisited []NodeType // classification of each node, in descent order
+	CacheHits        int        // nodes served from memory
+	CacheMisses      int        // nodes that needed backing-store access
+	IOOperations     int        // reads issued to the backing store
+	DurationNs       int64      // wall time of the instrumented descent
+}
+
+// Explain performs the descent a Find for key would take and reports what
+// it cost — the trie-level equivalent of EXPLAIN ANALYZE. The C layer does
+// not expose per-node cache state, so the cache and I/O counters are
+// derived: an in-memory database serves every node from memory, while for
+// a disk database each visited node is counted as a potential backing-
+// store read. This is a development and tuning tool, not a hot path; the
+// descent itself is re-executed with collection overhead included in
+// DurationNs.
+func (db *DB) Explain(root *Node, key []byte, version uint64) (*ExplainResult, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	result := &ExplainResult{}
+	start := time.Now()
+	err := db.WalkPath(root, key, version, func(node *Node, depth int, nibble byte) error {
+		result.PathLength++
+		result.NodeTypesVisited = append(result.NodeTypesVisited, node.Type())
+		return nil
+	})
+	result.DurationNs = time.Since(start).Nanoseconds()
+	if err != nil {
+		return nil, err
+	}
+
+	if db.IsOnDisk() {
+		result.CacheMisses = result.PathLength
+		result.IOOperations = result.PathLength
+	} else {
+		result.CacheHits = result.PathLength
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestExplain tests the lookup plan for a small trie.
+func TestExplain(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x12), Value: []byte("two")},
+		{Key: makeKey32(0x22), Value: []byte("three")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	result, err := db.Explain(root, makeKey32(0x11), 1)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if result.PathLength == 0 {
+		t.Fatal("Lookup should visit at least one node")
+	}
+	if len(result.NodeTypesVisited) != result.PathLength {
+		t.Errorf("Node types (%d) should match path length (%d)",
+			len(result.NodeTypesVisited), result.PathLength)
+	}
+	if last := result.NodeTypesVisited[result.PathLength-1]; last != NodeTypeLeaf {
+		t.Errorf("Descent should end at a leaf, got %v", last)
+	}
+	if result.CacheHits != result.PathLength || result.IOOperations != 0 {
+		t.Errorf("In-memory lookups should be all cache hits, got %+v", result)
+	}
+	if result.DurationNs <= 0 {
+		t.Error("Duration should be positive")
+	}
+}
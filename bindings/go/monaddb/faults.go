@@ -0,0 +1,92 @@
+package monaddb
+
+import (
+	"math/rand"
+	"time"
+)
+
+// FaultType selects which failure mode InjectFault simulates.
+type FaultType int
+
+const (
+	// FaultIOError makes reads and writes fail with ErrIO.
+	FaultIOError FaultType = iota + 1
+	// FaultCorruptRead flips a byte in values returned by reads.
+	FaultCorruptRead
+	// FaultSlowWrite delays writes by faultSlowWriteDelay.
+	FaultSlowWrite
+)
+
+// faultSlowWriteDelay is the latency a firing FaultSlowWrite adds to a
+// write.
+const faultSlowWriteDelay = 50 * time.Millisecond
+
+// faultPlan maps each injected fault to its firing probability. Plans are
+// immutable once stored; InjectFault swaps in a copy, so operations read a
+// consistent plan without locking.
+type faultPlan struct {
+	probabilities map[FaultType]float64
+}
+
+// InjectFault arms a simulated failure mode: each subsequent operation the
+// fault applies to fires it with the given probability. Probabilities are
+// clamped to [0, 1]; zero disarms that fault. The faults act purely in the
+// binding layer, so callers can be chaos-tested against I/O errors,
+// corrupted reads and latency spikes without touching the production C
+// code or the data on disk.
+func (db *DB) InjectFault(faultType FaultType, probability float64) {
+	if probability < 0 {
+		probability = 0
+	}
+	if probability > 1 {
+		probability = 1
+	}
+
+	plan := faultPlan{probabilities: make(map[FaultType]float64)}
+	if old := db.faults.Load(); old != nil {
+		for t, p := range old.probabilities {
+			plan.probabilities[t] = p
+		}
+	}
+	if probability == 0 {
+		delete(plan.probabilities, faultType)
+	} else {
+		plan.probabilities[faultType] = probability
+	}
+	if len(plan.probabilities) == 0 {
+		db.faults.Store(nil)
+		return
+	}
+	db.faults.Store(&plan)
+}
+
+// ClearFaults disarms every injected fault.
+func (db *DB) ClearFaults() {
+	db.faults.Store(nil)
+}
+
+// maybeCorrupt flips one byte of value when an armed FaultCorruptRead
+// fires. The stored data is untouched; only this read observes the
+// corruption.
+func (db *DB) maybeCorrupt(value []byte) []byte {
+	if len(value) == 0 || !db.faultFires(FaultCorruptRead) {
+		return value
+	}
+	corrupted := append([]byte(nil), value...)
+	corrupted[rand.Intn(len(corrupted))] ^= 0xFF
+	return corrupted
+}
+
+// faultFires reports whether an armed fault of the given type fires for
+// the current operation.
+func (db *DB) faultFires(faultType FaultType) bool {
+	plan := db.faults.Load()
+	if plan == nil {
+		return false
+	}
+	p, ok := plan.probabilities[faultType]
+	if !ok {
+		return false
+	}
+	return rand.Float64() < p
+}
@@ -0,0 +1,90 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestInjectFaultIOError tests that reads fail while the fault is armed.
+func TestInjectFaultIOError(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	db.InjectFault(FaultIOError, 1.0)
+	if _, err := db.FindFromRoot(root, makeKey32(0x01), 1); err != ErrIO {
+		t.Errorf("Armed IO fault should fail reads, got %v", err)
+	}
+	if _, err := db.Put(root, makeKey32(0x02), []byte("more"), 2); err != ErrIO {
+		t.Errorf("Armed IO fault should fail writes, got %v", err)
+	}
+
+	db.ClearFaults()
+	if _, err := db.FindFromRoot(root, makeKey32(0x01), 1); err != nil {
+		t.Errorf("Cleared faults should restore reads, got %v", err)
+	}
+}
+
+// TestInjectFaultCorruptRead tests that read values are corrupted in
+// flight while stored data stays intact.
+func TestInjectFaultCorruptRead(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	want := []byte("pristine value")
+	root, err := db.Put(nil, makeKey32(0x01), want, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	db.InjectFault(FaultCorruptRead, 1.0)
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	corrupted, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if bytes.Equal(corrupted, want) {
+		t.Error("Armed corrupt-read fault should alter the value")
+	}
+
+	db.ClearFaults()
+	node, err = db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || !bytes.Equal(value, want) {
+		t.Errorf("Stored data should be intact, got %q, %v", value, err)
+	}
+}
+
+// TestInjectFaultSlowWrite tests the added write latency.
+func TestInjectFaultSlowWrite(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	db.InjectFault(FaultSlowWrite, 1.0)
+	start := time.Now()
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < faultSlowWriteDelay {
+		t.Errorf("Armed slow-write fault should delay the write, took %v", elapsed)
+	}
+}
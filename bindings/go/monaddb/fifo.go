@@ -6,13 +6,18 @@ package monaddb
 */
 import "C"
 import (
+	"context"
+	"fmt"
 	"runtime"
+	"time"
 	"unsafe"
 )
 
 // EXPERIMENTAL: The async FIFO API is currently experimental and not fully
-// implemented. The underlying C API provides stub implementations that do not
-// perform actual async operations.
+// implemented. Find submissions execute synchronously in the submitting
+// goroutine (disk databases only) with their completions queued for Poll;
+// there is no worker-fiber pool yet, and traverse submissions remain
+// stubs that do nothing.
 //
 // For production use, prefer the synchronous API (Put, FindFromRoot, etc.).
 // For high-throughput scenarios requiring async operations, consider using
@@ -29,6 +34,12 @@ const (
 	StatusTraverseEnd  ResultStatus = 4
 )
 
+// Submission priorities, reported back in Completion.Priority.
+const (
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
 // Completion represents the result of an async operation.
 type Completion struct {
 	UserDataLo uint64
@@ -36,20 +47,42 @@ type Completion struct {
 	Status     ResultStatus
 	Value      []byte       // Inline value (up to 256 bytes)
 	MerkleHash [32]byte
+	Priority   int          // PriorityNormal or PriorityHigh
+}
+
+// Error converts the completion status to an error, allowing the usual
+// `if err := comp.Error(); err != nil` flow instead of switching on Status.
+// StatusOK and the traverse progress statuses (StatusTraverseMore,
+// StatusTraverseEnd) map to nil, StatusNotFound maps to ErrNotFound, and
+// anything else produces a descriptive error.
+func (c *Completion) Error() error {
+	switch c.Status {
+	case StatusOK, StatusTraverseMore, StatusTraverseEnd:
+		return nil
+	case StatusNotFound:
+		return ErrNotFound
+	case StatusError:
+		return fmt.Errorf("monaddb: async operation failed")
+	default:
+		return fmt.Errorf("monaddb: unknown completion status %d", int(c.Status))
+	}
 }
 
 // Fifo provides async operations using lock-free FIFO queues.
 // It is thread-safe and designed for high-concurrency scenarios.
 //
-// WARNING: This is currently a stub implementation. The async operations
-// do not actually perform any work. Use synchronous methods instead.
+// WARNING: The implementation is synchronous for now — find submissions
+// run in the submitting goroutine and traverse submissions do nothing
+// (see the note at the top of this file). Use synchronous methods for
+// anything the FIFO does not cover yet.
 type Fifo struct {
 	ptr *C.NomadFifo
 	db  *DB
 }
 
 // IsImplemented returns true if the async FIFO is fully implemented.
-// Currently returns false as the C API provides only stub implementations.
+// Currently returns false: finds execute synchronously at submit time and
+// traverse submissions are stubs — there is no true async worker pool.
 func (f *Fifo) IsImplemented() bool {
 	return false
 }
@@ -67,16 +100,64 @@ func (db *DB) CreateFifo() (*Fifo, error) {
 	}
 	
 	fifo := &Fifo{ptr: ptr, db: db}
+	db.fifoMu.Lock()
+	db.fifos = append(db.fifos, fifo)
+	db.fifoMu.Unlock()
 	runtime.SetFinalizer(fifo, (*Fifo).Destroy)
 	return fifo, nil
 }
 
+// defaultNumWorkers is the worker fiber count used when the application
+// has not configured one.
+const defaultNumWorkers = 4
+
+// NumWorkers returns the configured worker fiber count for async FIFOs.
+func (db *DB) NumWorkers() int {
+	if n := db.workers.Load(); n > 0 {
+		return int(n)
+	}
+	return defaultNumWorkers
+}
+
+// SetNumWorkers records the worker count for async FIFO requests and
+// re-issues start with the new count on every active FIFO; FIFOs created
+// later pick it up from Start. More workers would raise I/O parallelism
+// on high-bandwidth NVMe; fewer would reduce context switching during
+// batch processing.
+//
+// The C layer's worker pool is not implemented yet — submissions execute
+// synchronously (see the note at the top of this file) — so for now this
+// is configuration plumbing: the stored count takes effect once worker
+// fibers land.
+func (db *DB) SetNumWorkers(n int) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	if n <= 0 {
+		return ErrInvalidArgument
+	}
+	db.workers.Store(int32(n))
+
+	db.fifoMu.Lock()
+	defer db.fifoMu.Unlock()
+	for _, f := range db.fifos {
+		if f.ptr != nil {
+			C.nomad_fifo_start(f.ptr, C.size_t(n))
+		}
+	}
+	return nil
+}
+
 // Start starts the FIFO worker fibers.
-// numWorkers is the recommended number of worker fibers (typically 4-8).
+// numWorkers is the recommended number of worker fibers (typically 4-8);
+// values <= 0 use the database-level setting (see SetNumWorkers).
 func (f *Fifo) Start(numWorkers int) {
 	if f.ptr == nil {
 		return
 	}
+	if numWorkers <= 0 {
+		numWorkers = f.db.NumWorkers()
+	}
 	C.nomad_fifo_start(f.ptr, C.size_t(numWorkers))
 }
 
@@ -91,6 +172,16 @@ func (f *Fifo) Stop() {
 // Destroy destroys the FIFO and releases all resources.
 func (f *Fifo) Destroy() {
 	if f.ptr != nil {
+		if f.db != nil {
+			f.db.fifoMu.Lock()
+			for i, other := range f.db.fifos {
+				if other == f {
+					f.db.fifos = append(f.db.fifos[:i], f.db.fifos[i+1:]...)
+					break
+				}
+			}
+			f.db.fifoMu.Unlock()
+		}
 		C.nomad_fifo_destroy(f.ptr)
 		f.ptr = nil
 		runtime.SetFinalizer(f, nil)
@@ -131,8 +222,49 @@ func (f *Fifo) SubmitFind(key []byte, version uint64, userDataLo, userDataHi uin
 		C.uint64_t(userDataHi),
 	)
 	
-	// Note: The C implementation should free keyPtr after processing the request.
-	// Since the current C implementation is a stub, we free it here to prevent leaks.
+	// The C side executes the find during the call and does not take
+	// ownership of the key, so it is freed here.
+	if keyPtr != nil {
+		C.free(unsafe.Pointer(keyPtr))
+	}
+}
+
+// SubmitFindHighPriority submits a find request to the high-priority
+// queue, whose completions Poll() surfaces before any backlog of normal
+// completions, distinguished by Completion.Priority. Intended for
+// latency-sensitive RPC lookups that should not sit behind bulk
+// submissions. Note that with the current synchronous implementation the
+// find itself runs in the submitting goroutine; the priority governs
+// completion ordering, not execution order.
+//
+// Note: The key is copied internally, so it's safe to modify after this call returns.
+func (f *Fifo) SubmitFindHighPriority(key []byte, version uint64, userDataLo, userDataHi uint64) {
+	if f.ptr == nil {
+		return
+	}
+
+	var keyPtr *C.uint8_t
+	if len(key) > 0 {
+		// Allocate C memory for key to avoid CGO pointer rules violation
+		// The C side is responsible for freeing this memory after processing
+		keyPtr = (*C.uint8_t)(C.malloc(C.size_t(len(key))))
+		if keyPtr == nil {
+			return // Out of memory, silently fail
+		}
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(keyPtr)), len(key)), key)
+	}
+
+	C.nomad_fifo_submit_find_high_priority(
+		f.ptr,
+		keyPtr,
+		C.size_t(len(key)),
+		C.uint64_t(version),
+		C.uint64_t(userDataLo),
+		C.uint64_t(userDataHi),
+	)
+
+	// The C side executes the find during the call and does not take
+	// ownership of the key, so it is freed here.
 	if keyPtr != nil {
 		C.free(unsafe.Pointer(keyPtr))
 	}
@@ -197,6 +329,7 @@ func (f *Fifo) Poll() *Completion {
 		UserDataLo: uint64(cComp.user_data_lo),
 		UserDataHi: uint64(cComp.user_data_hi),
 		Status:     ResultStatus(cComp.status),
+		Priority:   int(cComp.priority),
 	}
 	
 	// Copy value if present
@@ -212,6 +345,36 @@ func (f *Fifo) Poll() *Completion {
 	return comp
 }
 
+// CompletionChannel returns a channel fed by a background goroutine that
+// polls the find queue, sleeping briefly whenever no completion is ready.
+// This lets FIFO completions participate in a select statement instead of a
+// hand-written poll loop. When ctx is cancelled the goroutine exits and the
+// channel is closed; a send already in flight completes first if a receiver
+// is listening.
+func (f *Fifo) CompletionChannel(ctx context.Context, bufSize int) <-chan *Completion {
+	ch := make(chan *Completion, bufSize)
+	go func() {
+		defer close(ch)
+		for {
+			comp := f.Poll()
+			if comp == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(100 * time.Microsecond):
+				}
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- comp:
+			}
+		}
+	}()
+	return ch
+}
+
 // PollTraverse retrieves a completion from the traverse queue (non-blocking).
 // Returns nil if no completion is available.
 func (f *Fifo) PollTraverse() *Completion {
@@ -228,6 +391,7 @@ func (f *Fifo) PollTraverse() *Completion {
 		UserDataLo: uint64(cComp.user_data_lo),
 		UserDataHi: uint64(cComp.user_data_hi),
 		Status:     ResultStatus(cComp.status),
+		Priority:   int(cComp.priority),
 	}
 	
 	// Copy value if present
@@ -278,3 +442,43 @@ func (f *Fifo) PollLargeValue() *LargeValue {
 }
 
 
+
+// QueueDepth reports how many submitted requests have not yet surfaced as
+// completions, across both the find and traverse queues.
+func (f *Fifo) QueueDepth() int {
+	if f.ptr == nil {
+		return 0
+	}
+	return int(C.nomad_fifo_queue_depth(f.ptr))
+}
+
+// Drain collects every available completion from both the find and traverse
+// queues, blocking until QueueDepth reaches zero and both queues are empty.
+// This is the graceful-shutdown primitive: call Stop, then Drain, to be sure
+// every in-flight operation has been collected before cleanup. Returns the
+// completions gathered so far along with ctx.Err() if the context expires
+// first.
+func (f *Fifo) Drain(ctx context.Context) ([]*Completion, error) {
+	var out []*Completion
+	for {
+		progressed := false
+		if comp := f.Poll(); comp != nil {
+			out = append(out, comp)
+			progressed = true
+		}
+		if comp := f.PollTraverse(); comp != nil {
+			out = append(out, comp)
+			progressed = true
+		}
+		if !progressed {
+			if f.QueueDepth() == 0 {
+				return out, nil
+			}
+			select {
+			case <-ctx.Done():
+				return out, ctx.Err()
+			case <-time.After(100 * time.Microsecond):
+			}
+		}
+	}
+}
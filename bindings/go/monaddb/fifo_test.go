@@ -0,0 +1,166 @@
+package monaddb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestCompletionError tests the status-to-error conversion.
+func TestCompletionError(t *testing.T) {
+	cases := []struct {
+		status  ResultStatus
+		wantNil bool
+		want    error
+	}{
+		{StatusOK, true, nil},
+		{StatusTraverseMore, true, nil},
+		{StatusTraverseEnd, true, nil},
+		{StatusNotFound, false, ErrNotFound},
+		{StatusError, false, nil},
+		{ResultStatus(99), false, nil},
+	}
+	for _, c := range cases {
+		err := (&Completion{Status: c.status}).Error()
+		if c.wantNil {
+			if err != nil {
+				t.Errorf("Status %d should convert to nil, got %v", c.status, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("Status %d should convert to an error", c.status)
+			continue
+		}
+		if c.want != nil && !errors.Is(err, c.want) {
+			t.Errorf("Status %d should convert to %v, got %v", c.status, c.want, err)
+		}
+	}
+}
+
+// TestCompletionChannel tests that the poll goroutine honors cancellation.
+// Nothing is submitted, so no completions arrive; the channel must still
+// close promptly once the context is cancelled.
+func TestCompletionChannel(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	fifo, err := db.CreateFifo()
+	if err != nil {
+		t.Fatalf("CreateFifo failed: %v", err)
+	}
+	defer fifo.Destroy()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := fifo.CompletionChannel(ctx, 8)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Stub FIFO should not deliver completions")
+		}
+	case <-time.After(time.Second):
+		t.Error("Channel should close after context cancellation")
+	}
+}
+
+// TestDrain tests shutdown collection, idle and with queued completions.
+func TestDrain(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	fifo, err := db.CreateFifo()
+	if err != nil {
+		t.Fatalf("CreateFifo failed: %v", err)
+	}
+	defer fifo.Destroy()
+
+	fifo.Stop()
+	comps, err := fifo.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(comps) != 0 {
+		t.Errorf("Idle FIFO should drain empty, got %d completions", len(comps))
+	}
+	if fifo.QueueDepth() != 0 {
+		t.Errorf("QueueDepth should be 0 after drain, got %d", fifo.QueueDepth())
+	}
+
+	// Submitted work queues completions that Drain must collect
+	fifo.SubmitFind(makeKey32(0x01), 1, 1, 0)
+	fifo.SubmitFindHighPriority(makeKey32(0x02), 1, 2, 0)
+	if fifo.QueueDepth() != 2 {
+		t.Errorf("QueueDepth should count queued completions, got %d", fifo.QueueDepth())
+	}
+	comps, err = fifo.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(comps) != 2 {
+		t.Fatalf("Drain should collect every completion, got %d", len(comps))
+	}
+	if comps[0].Priority != PriorityHigh {
+		t.Error("High-priority completions should surface first")
+	}
+	if fifo.QueueDepth() != 0 {
+		t.Errorf("QueueDepth should return to 0, got %d", fifo.QueueDepth())
+	}
+}
+
+// TestSetNumWorkersConcurrent tests the resize bookkeeping under
+// concurrent submits. The C worker pool is not implemented yet, so this
+// covers the configuration plumbing: count storage, propagation to active
+// FIFOs, and safety alongside in-flight submissions.
+func TestSetNumWorkersConcurrent(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if db.NumWorkers() != defaultNumWorkers {
+		t.Errorf("Default worker count should be %d, got %d", defaultNumWorkers, db.NumWorkers())
+	}
+	if err := db.SetNumWorkers(0); err != ErrInvalidArgument {
+		t.Errorf("Zero workers should return ErrInvalidArgument, got %v", err)
+	}
+
+	fifo, err := db.CreateFifo()
+	if err != nil {
+		t.Fatalf("CreateFifo failed: %v", err)
+	}
+	defer fifo.Destroy()
+	fifo.Start(0) // picks up the database-level setting
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				fifo.SubmitFind(makeKey32(byte(i)), 1, uint64(g), uint64(i))
+				fifo.Poll()
+			}
+		}(g)
+	}
+	for n := 1; n <= 8; n++ {
+		if err := db.SetNumWorkers(n); err != nil {
+			t.Errorf("SetNumWorkers(%d) failed: %v", n, err)
+		}
+	}
+	wg.Wait()
+
+	if db.NumWorkers() != 8 {
+		t.Errorf("Worker count should be 8 after the last resize, got %d", db.NumWorkers())
+	}
+}
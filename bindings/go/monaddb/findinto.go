@@ -0,0 +1,63 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import "unsafe"
+
+// FindInto looks up key and passes its value to fn without copying it
+// into Go memory: the slice is backed by C memory that is freed as soon
+// as fn returns.
+//
+// The slice passed to fn MUST NOT be retained, stored, or returned —
+// not even a sub-slice. Copy it first if the bytes are needed after fn
+// returns. fn is not called when the key is absent; FindInto then
+// returns ErrNotFound.
+//
+// Values stored through a codec (compression or encryption) have to be
+// decoded before fn sees them, which allocates; the zero-copy fast path
+// applies to values stored raw.
+func (db *DB) FindInto(root *Node, key []byte, version uint64, fn func(value []byte) error) error {
+	if db == nil || db.ptr == nil || fn == nil {
+		return ErrNullPointer
+	}
+	if root == nil || root.ptr == nil {
+		return ErrNotFound
+	}
+
+	var keyPtr *C.uint8_t
+	if len(key) > 0 {
+		keyPtr = (*C.uint8_t)(unsafe.Pointer(&key[0]))
+	}
+
+	var nodePtr *C.NomadNode
+	code := C.nomad_db_find_from_root(db.ptr, root.ptr, keyPtr, C.size_t(len(key)), C.uint64_t(version), &nodePtr)
+	if err := codeToError(int(code)); err != nil {
+		return err
+	}
+	if nodePtr == nil {
+		return ErrNotFound
+	}
+	defer C.nomad_node_free(nodePtr)
+
+	var bytes C.NomadBytes
+	code = C.nomad_node_get_value(nodePtr, &bytes)
+	if err := codeToError(int(code)); err != nil {
+		return err
+	}
+	if bytes.data == nil || bytes.len == 0 {
+		return fn(nil)
+	}
+	defer C.nomad_bytes_free(&bytes)
+
+	value := unsafe.Slice((*byte)(unsafe.Pointer(bytes.data)), int(bytes.len))
+	if hasValueHeader(value) {
+		decoded, err := db.decodeValue(value)
+		if err != nil {
+			return err
+		}
+		return fn(decoded)
+	}
+	return fn(value)
+}
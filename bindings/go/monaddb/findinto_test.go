@@ -0,0 +1,52 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestFindInto tests the zero-copy lookup callback.
+func TestFindInto(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	value := []byte("process me in place")
+	root, err := db.Put(nil, key, value, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	var called bool
+	err = db.FindInto(root, key, 1, func(v []byte) error {
+		called = true
+		if !bytes.Equal(v, value) {
+			t.Errorf("Callback value mismatch: got %q", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FindInto failed: %v", err)
+	}
+	if !called {
+		t.Error("Callback should have been invoked")
+	}
+
+	// Missing keys surface as ErrNotFound without invoking fn
+	err = db.FindInto(root, makeKey32(0x02), 1, func([]byte) error {
+		t.Error("Callback must not run for a missing key")
+		return nil
+	})
+	if err != ErrNotFound {
+		t.Errorf("FindInto on a missing key should return ErrNotFound, got %v", err)
+	}
+
+	// A callback error propagates unchanged
+	sentinel := ErrInvalidArgument
+	if err := db.FindInto(root, key, 1, func([]byte) error { return sentinel }); err != sentinel {
+		t.Errorf("Callback error should propagate, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package monaddb
+
+import "os"
+
+// Sync blocks until all buffered writes are applied and the database file
+// is durable on disk. Any auto-batched writes are flushed first, then the
+// backing file is fsynced. A no-op for in-memory databases beyond the
+// batch flush.
+func (db *DB) Sync() error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+
+	if ab := db.autoBatch.Load(); ab != nil {
+		if _, err := ab.flush(); err != nil {
+			return err
+		}
+	}
+
+	if db.path == "" {
+		return nil
+	}
+	f, err := os.Open(db.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	db.log().Debug("database synced", "path", db.path)
+	return nil
+}
+
+// FlushAsync starts a Sync in the background and returns a channel that
+// receives the result (nil on success) and is then closed. The caller can
+// select on it alongside other work — for example, consensus code can kick
+// off the flush for one block's state and begin executing the next block
+// while the fsync completes.
+func (db *DB) FlushAsync() <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		if err := db.Sync(); err != nil {
+			ch <- err
+		}
+	}()
+	return ch
+}
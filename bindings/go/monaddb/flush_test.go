@@ -0,0 +1,54 @@
+package monaddb
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFlushAsync tests that the async flush completes and closes its channel.
+func TestFlushAsync(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/flush.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	select {
+	case err, ok := <-db.FlushAsync():
+		if ok && err != nil {
+			t.Errorf("FlushAsync reported error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("FlushAsync should complete")
+	}
+}
+
+// TestSyncFlushesAutoBatch tests that Sync drains queued writes.
+func TestSyncFlushesAutoBatch(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetAutoBatch(time.Hour, 1000); err != nil {
+		t.Fatalf("SetAutoBatch failed: %v", err)
+	}
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if db.BatchPending() != 1 {
+		t.Fatalf("Expected 1 pending write, got %d", db.BatchPending())
+	}
+
+	if err := db.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if db.BatchPending() != 0 {
+		t.Errorf("Sync should flush pending writes, got %d", db.BatchPending())
+	}
+}
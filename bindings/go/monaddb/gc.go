@@ -0,0 +1,128 @@
+package monaddb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// gcScheduler drives the automatic garbage collection loop. The goroutine
+// starts lazily on the first SetGCInterval call and exits when the
+// database closes.
+type gcScheduler struct {
+	mu       sync.Mutex
+	interval time.Duration
+	changed  chan struct{}
+	started  bool
+	closed   bool
+	runs     atomic.Uint64
+}
+
+// signal wakes the loop to pick up a new interval or shut down.
+func (g *gcScheduler) signal() {
+	select {
+	case g.changed <- struct{}{}:
+	default:
+	}
+}
+
+// SetGCInterval schedules automatic garbage collection: a background
+// goroutine calls TriggerGC every interval. An interval of zero disables
+// the schedule (the default). The goroutine starts on the first call and
+// stops when the database is closed; GC runs at idle I/O priority like
+// the other maintenance passes.
+func (db *DB) SetGCInterval(interval time.Duration) {
+	if interval < 0 {
+		interval = 0
+	}
+	db.gc.mu.Lock()
+	db.gc.interval = interval
+	if !db.gc.started {
+		db.gc.started = true
+		db.gc.changed = make(chan struct{}, 1)
+		go db.gcLoop()
+	}
+	db.gc.mu.Unlock()
+	db.gc.signal()
+}
+
+// gcLoop sleeps for the configured interval between TriggerGC passes,
+// waking early when the interval changes or the database closes.
+func (db *DB) gcLoop() {
+	for {
+		db.gc.mu.Lock()
+		interval, closed := db.gc.interval, db.gc.closed
+		db.gc.mu.Unlock()
+		if closed {
+			return
+		}
+
+		if interval == 0 {
+			<-db.gc.changed
+			continue
+		}
+		select {
+		case <-time.After(interval):
+			db.TriggerGC(context.Background())
+		case <-db.gc.changed:
+		}
+	}
+}
+
+// stopGC shuts the scheduler down; called from Close.
+func (db *DB) stopGC() {
+	db.gc.mu.Lock()
+	started := db.gc.started
+	db.gc.closed = true
+	db.gc.mu.Unlock()
+	if started {
+		db.gc.signal()
+	}
+}
+
+// TriggerGC runs one garbage collection pass now. The C engine reclaims
+// trie storage on its own as versions age out of retention; what outlives
+// that are the Go-side per-version records — access times, commit
+// metadata, sentinels — which this pass drops for versions older than the
+// earliest retained one. The pass only takes short locks and does not
+// block writes. The context is checked between steps.
+func (db *DB) TriggerGC(ctx context.Context) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	db.gc.runs.Add(1)
+
+	earliest := db.EarliestVersion()
+
+	db.versionTimes.mu.Lock()
+	for v := range db.versionTimes.times {
+		if v < earliest {
+			delete(db.versionTimes.times, v)
+		}
+	}
+	db.versionTimes.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.versionMeta.mu.Lock()
+	for v := range db.versionMeta.meta {
+		if v < earliest {
+			delete(db.versionMeta.meta, v)
+		}
+	}
+	db.versionMeta.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.sentinels.mu.Lock()
+	for v := range db.sentinels.macs {
+		if v < earliest {
+			delete(db.sentinels.macs, v)
+		}
+	}
+	db.sentinels.mu.Unlock()
+	return nil
+}
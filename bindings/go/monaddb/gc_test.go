@@ -0,0 +1,70 @@
+package monaddb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSetGCInterval tests that scheduled GC runs within its window.
+func TestSetGCInterval(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	db.SetGCInterval(20 * time.Millisecond)
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for db.gc.runs.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("GC should run at least once within 200ms")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Disabling stops further passes
+	db.SetGCInterval(0)
+	time.Sleep(30 * time.Millisecond)
+	runs := db.gc.runs.Load()
+	time.Sleep(60 * time.Millisecond)
+	if got := db.gc.runs.Load(); got != runs {
+		t.Errorf("Disabled schedule should not run GC, got %d extra passes", got-runs)
+	}
+}
+
+// TestTriggerGC tests that a pass drops records for aged-out versions.
+func TestTriggerGC(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/gc.db", true, 2)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 5; v++ {
+		root, err = db.UpsertWithMetadata(root, []Update{
+			{Key: makeKey32(byte(v)), Value: []byte("value")},
+		}, v, map[string]string{"n": "1"})
+		if err != nil {
+			t.Fatalf("Upsert at version %d failed: %v", v, err)
+		}
+	}
+
+	if err := db.TriggerGC(context.Background()); err != nil {
+		t.Fatalf("TriggerGC failed: %v", err)
+	}
+
+	earliest := db.EarliestVersion()
+	db.versionMeta.mu.Lock()
+	for v := range db.versionMeta.meta {
+		if v < earliest {
+			t.Errorf("Metadata for aged-out version %d should be gone", v)
+		}
+	}
+	if _, ok := db.versionMeta.meta[5]; !ok {
+		t.Error("Metadata for retained versions should survive")
+	}
+	db.versionMeta.mu.Unlock()
+}
@@ -0,0 +1,96 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// GraftSubtree inserts every key of subtreeRoot into the trie rooted at
+// root, with prefix prepended to each key, and returns the new root. The
+// graft is structural — whole subtrees are shared rather than re-inserted
+// leaf by leaf — yet the resulting root hash equals what upserting each
+// prefixed key-value pair individually would produce. This is the fast
+// path for combining state from multiple sources, such as applying a
+// storage trie received during state sync under its account's prefix.
+func (db *DB) GraftSubtree(root *Node, prefix []byte, subtreeRoot *Node, version uint64) (*Node, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if subtreeRoot == nil || subtreeRoot.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var rootPtr *C.NomadNode
+	if root != nil {
+		rootPtr = root.ptr
+	}
+
+	var prefixPtr *C.uint8_t
+	if len(prefix) > 0 {
+		prefixPtr = (*C.uint8_t)(unsafe.Pointer(&prefix[0]))
+	}
+
+	var newRootPtr *C.NomadNode
+	code := C.nomad_db_graft_subtree(
+		db.ptr,
+		rootPtr,
+		prefixPtr,
+		C.size_t(len(prefix)),
+		subtreeRoot.ptr,
+		C.uint64_t(version),
+		&newRootPtr,
+	)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+	if newRootPtr == nil {
+		return nil, nil
+	}
+
+	newRoot := &Node{ptr: newRootPtr, db: db}
+	runtime.SetFinalizer(newRoot, (*Node).Free)
+	return newRoot, nil
+}
+
+// ExtractSubtree returns a root containing only the keys under prefix,
+// each with the prefix stripped — the inverse of GraftSubtree. A nil root
+// with a nil error means no keys exist under the prefix. The typical use
+// is pulling one account's storage trie out of the state trie so it can
+// be served for state sync or proof generation on its own.
+func (db *DB) ExtractSubtree(root *Node, prefix []byte, version uint64) (*Node, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if root == nil || root.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var prefixPtr *C.uint8_t
+	if len(prefix) > 0 {
+		prefixPtr = (*C.uint8_t)(unsafe.Pointer(&prefix[0]))
+	}
+
+	var newRootPtr *C.NomadNode
+	code := C.nomad_db_extract_subtree(
+		db.ptr,
+		root.ptr,
+		prefixPtr,
+		C.size_t(len(prefix)),
+		C.uint64_t(version),
+		&newRootPtr,
+	)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+	if newRootPtr == nil {
+		return nil, nil
+	}
+
+	newRoot := &Node{ptr: newRootPtr, db: db}
+	runtime.SetFinalizer(newRoot, (*Node).Free)
+	return newRoot, nil
+}
@@ -0,0 +1,127 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestGraftSubtree tests that grafting matches key-by-key insertion.
+func TestGraftSubtree(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	// Build the subtree to graft
+	subUpdates := []Update{
+		{Key: []byte{0x11, 0x11}, Value: []byte("a")},
+		{Key: []byte{0x22, 0x22}, Value: []byte("b")},
+	}
+	subRoot, err := db.Upsert(nil, subUpdates, 1)
+	if err != nil {
+		t.Fatalf("Upsert subtree failed: %v", err)
+	}
+
+	prefix := []byte{0xAA, 0xBB}
+	grafted, err := db.GraftSubtree(nil, prefix, subRoot, 2)
+	if err != nil {
+		t.Fatalf("GraftSubtree failed: %v", err)
+	}
+
+	// The reference trie inserts each prefixed pair individually
+	refUpdates := make([]Update, len(subUpdates))
+	for i, u := range subUpdates {
+		refUpdates[i] = Update{
+			Key:   append(append([]byte(nil), prefix...), u.Key...),
+			Value: u.Value,
+		}
+	}
+	refRoot, err := db.Upsert(nil, refUpdates, 2)
+	if err != nil {
+		t.Fatalf("Upsert reference failed: %v", err)
+	}
+
+	graftedHash, err := grafted.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	refHash, err := refRoot.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if graftedHash != refHash {
+		t.Error("Grafted root hash should match individual insertion")
+	}
+
+	// Every grafted key is readable under its prefixed location
+	for _, u := range refUpdates {
+		node, err := db.FindFromRoot(grafted, u.Key, 2)
+		if err != nil {
+			t.Fatalf("Find %x failed: %v", u.Key, err)
+		}
+		value, err := node.Value()
+		if err != nil || string(value) != string(u.Value) {
+			t.Errorf("Key %x should carry value %q, got %q, %v", u.Key, u.Value, value, err)
+		}
+	}
+}
+
+// TestExtractSubtree tests that extraction inverts a graft.
+func TestExtractSubtree(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	prefix := []byte{0xAA, 0xBB}
+	updates := []Update{
+		{Key: append(append([]byte(nil), prefix...), 0x11, 0x11), Value: []byte("a")},
+		{Key: append(append([]byte(nil), prefix...), 0x22, 0x22), Value: []byte("b")},
+		{Key: []byte{0xCC, 0xDD, 0x33, 0x33}, Value: []byte("outside")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	extracted, err := db.ExtractSubtree(root, prefix, 2)
+	if err != nil {
+		t.Fatalf("ExtractSubtree failed: %v", err)
+	}
+	if extracted == nil {
+		t.Fatal("Extraction should return a root for a populated prefix")
+	}
+
+	// Stripped keys resolve; the key outside the prefix does not
+	node, err := db.FindFromRoot(extracted, []byte{0x11, 0x11}, 2)
+	if err != nil {
+		t.Fatalf("Find stripped key failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "a" {
+		t.Errorf("Stripped key should carry value %q, got %q, %v", "a", value, err)
+	}
+	if outside, err := db.FindFromRoot(extracted, []byte{0x33, 0x33}, 2); err != nil || outside != nil {
+		t.Errorf("Key outside the prefix should be absent, got %v, %v", outside, err)
+	}
+
+	// The extracted trie matches one built from the stripped pairs alone
+	refRoot, err := db.Upsert(nil, []Update{
+		{Key: []byte{0x11, 0x11}, Value: []byte("a")},
+		{Key: []byte{0x22, 0x22}, Value: []byte("b")},
+	}, 2)
+	if err != nil {
+		t.Fatalf("Upsert reference failed: %v", err)
+	}
+	extractedHash, err := extracted.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	refHash, err := refRoot.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if extractedHash != refHash {
+		t.Error("Extracted root hash should match a directly built trie")
+	}
+}
@@ -0,0 +1,98 @@
+package monaddb
+
+import (
+	"bytes"
+	"sort"
+)
+
+// HashState computes keccak256 over the sorted keys and their values,
+// giving a cheap aggregate commitment to a subset of the state (for
+// example a set of account balances) without building Merkle proofs.
+//
+// Keys are sorted and de-duplicated before hashing, so the result is
+// deterministic regardless of the order the caller assembled them in.
+// Absent keys contribute their key with an empty value, which commits to
+// the absence as well.
+func (db *DB) HashState(root *Node, keys [][]byte, version uint64) ([32]byte, error) {
+	var zero [32]byte
+	if db == nil || db.ptr == nil {
+		return zero, ErrNullPointer
+	}
+
+	sorted := make([][]byte, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	var buf []byte
+	var prev []byte
+	for i, key := range sorted {
+		if i > 0 && bytes.Equal(key, prev) {
+			continue
+		}
+		prev = key
+
+		buf = append(buf, key...)
+		node, err := db.FindFromRoot(root, key, version)
+		if err != nil && err != ErrNotFound {
+			return zero, err
+		}
+		if node != nil && node.HasValue() {
+			value, err := node.Value()
+			if err != nil {
+				return zero, err
+			}
+			buf = append(buf, value...)
+		}
+	}
+
+	return db.KeyHash(buf), nil
+}
+
+// ComputeChecksum computes a deterministic aggregate checksum over the
+// entire state reachable from root: the XOR of keccak256(key || value) for
+// every entry. XOR is order-independent, so two databases holding the same
+// key-value set produce the same checksum no matter how their tries were
+// built. This is a far cheaper consistency check than proof generation and
+// is intended for state-sync validation; unlike the Merkle root it does not
+// commit to structure, only to content.
+func (db *DB) ComputeChecksum(root *Node, version uint64) ([]byte, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	checksum := make([]byte, 32)
+	err := db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		entry := db.KeyHash(append(append([]byte(nil), kv.Key...), kv.Value...))
+		for i := range checksum {
+			checksum[i] ^= entry[i]
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checksum, nil
+}
+
+// ValidateStateRoot checks that the trie's root hash matches what
+// consensus expects, returning a *StateMismatchError (matching
+// ErrStateMismatch under errors.Is) carrying both hashes when it does
+// not. This is the canonical post-execution validation call: compute the
+// state transition, then validate the resulting root against the block
+// header before committing.
+func (db *DB) ValidateStateRoot(root *Node, expectedHash [32]byte) error {
+	if root == nil || root.ptr == nil {
+		return ErrNullPointer
+	}
+
+	actual, err := root.Hash()
+	if err != nil {
+		return err
+	}
+	if actual != expectedHash {
+		return &StateMismatchError{Expected: expectedHash, Actual: actual}
+	}
+	return nil
+}
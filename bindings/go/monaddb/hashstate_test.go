@@ -0,0 +1,158 @@
+package monaddb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestHashState tests the aggregate commitment and its order independence.
+func TestHashState(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	a := makeKey32(0x0A)
+	b := makeKey32(0x0B)
+	root, err := db.Upsert(nil, []Update{
+		{Type: UpdatePut, Key: a, Value: []byte("alpha")},
+		{Type: UpdatePut, Key: b, Value: []byte("beta")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	h1, err := db.HashState(root, [][]byte{a, b}, 1)
+	if err != nil {
+		t.Fatalf("HashState failed: %v", err)
+	}
+	h2, err := db.HashState(root, [][]byte{b, a}, 1)
+	if err != nil {
+		t.Fatalf("HashState failed: %v", err)
+	}
+	if h1 != h2 {
+		t.Error("HashState should be independent of key order")
+	}
+
+	// Changing a covered value changes the commitment
+	root2, err := db.Put(root, a, []byte("changed"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	h3, err := db.HashState(root2, [][]byte{a, b}, 1)
+	if err != nil {
+		t.Fatalf("HashState failed: %v", err)
+	}
+	if h3 == h1 {
+		t.Error("Commitment should change when a covered value changes")
+	}
+
+	// A subset excluding the changed key is unaffected
+	h4, err := db.HashState(root, [][]byte{b}, 1)
+	if err != nil {
+		t.Fatalf("HashState failed: %v", err)
+	}
+	h5, err := db.HashState(root2, [][]byte{b}, 1)
+	if err != nil {
+		t.Fatalf("HashState failed: %v", err)
+	}
+	if h4 != h5 {
+		t.Error("Commitment over an untouched subset should be stable")
+	}
+}
+
+// TestComputeChecksum tests insertion-order independence of the aggregate.
+func TestComputeChecksum(t *testing.T) {
+	a, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer a.Close()
+	b, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer b.Close()
+
+	updates := []Update{
+		{Type: UpdatePut, Key: makeKey32(0x01), Value: []byte("one")},
+		{Type: UpdatePut, Key: makeKey32(0x02), Value: []byte("two")},
+		{Type: UpdatePut, Key: makeKey32(0x03), Value: []byte("three")},
+	}
+
+	rootA, err := a.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	// Same content inserted in reverse order
+	var rootB *Node
+	for i := len(updates) - 1; i >= 0; i-- {
+		rootB, err = b.Put(rootB, updates[i].Key, updates[i].Value, 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	sumA, err := a.ComputeChecksum(rootA, 1)
+	if err != nil {
+		t.Fatalf("ComputeChecksum failed: %v", err)
+	}
+	sumB, err := b.ComputeChecksum(rootB, 1)
+	if err != nil {
+		t.Fatalf("ComputeChecksum failed: %v", err)
+	}
+	if !bytes.Equal(sumA, sumB) {
+		t.Error("Identical state should produce identical checksums")
+	}
+
+	// A content change must move the checksum
+	rootB, err = b.Put(rootB, makeKey32(0x03), []byte("changed"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	sumC, err := b.ComputeChecksum(rootB, 1)
+	if err != nil {
+		t.Fatalf("ComputeChecksum failed: %v", err)
+	}
+	if bytes.Equal(sumA, sumC) {
+		t.Error("Different state should produce different checksums")
+	}
+}
+
+// TestValidateStateRoot tests the match and mismatch paths.
+func TestValidateStateRoot(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	hash, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+
+	if err := db.ValidateStateRoot(root, hash); err != nil {
+		t.Errorf("Matching hash should validate, got %v", err)
+	}
+
+	var wrong [32]byte
+	wrong[0] = 0xFF
+	err = db.ValidateStateRoot(root, wrong)
+	if !errors.Is(err, ErrStateMismatch) {
+		t.Fatalf("Expected ErrStateMismatch, got %v", err)
+	}
+	var mismatch *StateMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatal("Error should be a *StateMismatchError")
+	}
+	if mismatch.Expected != wrong || mismatch.Actual != hash {
+		t.Error("Mismatch error should carry both hashes")
+	}
+}
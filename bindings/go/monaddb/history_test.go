@@ -0,0 +1,215 @@
+package monaddb
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestDumpVersionHistory tests the JSON history dump and its determinism.
+func TestDumpVersionHistory(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/history.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 3; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put at version %d failed: %v", v, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := db.DumpVersionHistory(&buf); err != nil {
+		t.Fatalf("DumpVersionHistory failed: %v", err)
+	}
+
+	var entries []versionHistoryEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(entries))
+	}
+	for i, e := range entries {
+		if e.Version != uint64(i+1) {
+			t.Errorf("Entry %d version should be %d, got %d", i, i+1, e.Version)
+		}
+		if len(e.RootHash) != 66 {
+			t.Errorf("Entry %d rootHash should be 0x-prefixed 32 bytes, got %q", i, e.RootHash)
+		}
+		if e.KeyCount != uint64(i+1) {
+			t.Errorf("Entry %d keyCount should be %d, got %d", i, i+1, e.KeyCount)
+		}
+	}
+
+	// Same history, same bytes
+	var again bytes.Buffer
+	if err := db.DumpVersionHistory(&again); err != nil {
+		t.Fatalf("DumpVersionHistory failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Error("Dump output should be deterministic")
+	}
+}
+
+// TestLatestRoot tests the combined latest-version root load.
+func TestLatestRoot(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/latest.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 3; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	latest, err := db.LatestRoot()
+	if err != nil {
+		t.Fatalf("LatestRoot failed: %v", err)
+	}
+	if latest == nil {
+		t.Fatal("LatestRoot should not be nil after writes")
+	}
+
+	want, err := db.LoadRoot(db.LatestVersion())
+	if err != nil {
+		t.Fatalf("LoadRoot failed: %v", err)
+	}
+	wantHash, err := want.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	gotHash, err := latest.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if gotHash != wantHash {
+		t.Error("LatestRoot should match LoadRoot(LatestVersion())")
+	}
+}
+
+// TestHistoricalRootHash tests the node-free root hash read.
+func TestHistoricalRootHash(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/roothash.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 3; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	for v := uint64(1); v <= 3; v++ {
+		got, err := db.HistoricalRootHash(v)
+		if err != nil {
+			t.Fatalf("HistoricalRootHash(%d) failed: %v", v, err)
+		}
+		node, err := db.LoadRoot(v)
+		if err != nil {
+			t.Fatalf("LoadRoot(%d) failed: %v", v, err)
+		}
+		want, err := node.Hash()
+		if err != nil {
+			t.Fatalf("Hash failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Version %d hash mismatch with LoadRoot().Hash()", v)
+		}
+	}
+}
+
+// TestTouchVersion tests access-time recording and its history output.
+func TestTouchVersion(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/touch.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if err := db.TouchVersion(1); err != nil {
+		t.Fatalf("TouchVersion failed: %v", err)
+	}
+	if err := db.TouchVersion(99); err != ErrVersionOutOfRange {
+		t.Errorf("Touching an invalid version should return ErrVersionOutOfRange, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := db.DumpVersionHistory(&buf); err != nil {
+		t.Fatalf("DumpVersionHistory failed: %v", err)
+	}
+	var entries []versionHistoryEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Timestamp == "" {
+		t.Errorf("Touched version should carry a timestamp, got %+v", entries)
+	}
+}
+
+// TestLoadVersionBatch tests loading a range of roots in one call.
+func TestLoadVersionBatch(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/batch.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 3; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte("value"), v)
+		if err != nil {
+			t.Fatalf("Put at version %d failed: %v", v, err)
+		}
+	}
+
+	roots, err := db.LoadVersionBatch([]uint64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("LoadVersionBatch failed: %v", err)
+	}
+	if len(roots) != 3 {
+		t.Fatalf("Expected 3 roots, got %d", len(roots))
+	}
+	for i, batchRoot := range roots {
+		v := uint64(i + 1)
+		if batchRoot == nil {
+			t.Fatalf("Version %d should have a root", v)
+		}
+		single, err := db.LoadRoot(v)
+		if err != nil {
+			t.Fatalf("LoadRoot(%d) failed: %v", v, err)
+		}
+		want, err := single.Hash()
+		if err != nil {
+			t.Fatalf("Hash failed: %v", err)
+		}
+		got, err := batchRoot.Hash()
+		if err != nil {
+			t.Fatalf("Hash failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Version %d batch root hash mismatch with LoadRoot", v)
+		}
+	}
+
+	if roots, err = db.LoadVersionBatch(nil); err != nil || roots != nil {
+		t.Errorf("Empty batch should be a no-op, got %v, %v", roots, err)
+	}
+}
@@ -0,0 +1,104 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+
+// IOPriority is the I/O scheduling priority requested for database threads.
+// The zero value means "not configured".
+type IOPriority int
+
+const (
+	IOPriorityHigh IOPriority = iota + 1
+	IOPriorityNormal
+	IOPriorityIdle
+)
+
+// cIOPriority maps an IOPriority to the C enum value.
+func cIOPriority(p IOPriority) (C.NomadIOPriority, bool) {
+	switch p {
+	case IOPriorityHigh:
+		return C.NOMAD_IOPRIO_HIGH, true
+	case IOPriorityNormal:
+		return C.NOMAD_IOPRIO_NORMAL, true
+	case IOPriorityIdle:
+		return C.NOMAD_IOPRIO_IDLE, true
+	}
+	return 0, false
+}
+
+// SetIOPriority requests an I/O scheduling priority for the database's
+// threads, letting colocated services share a disk without monaddb
+// monopolizing it. Linux only (via ioprio_set); other platforms return
+// ErrNotSupported.
+//
+// Background maintenance (Defragment, Checkpoint) always drops to the
+// lowest priority for the duration of the pass regardless of this setting,
+// unless Options.GCIOPriority overrides it.
+func (db *DB) SetIOPriority(priority IOPriority) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	cp, ok := cIOPriority(priority)
+	if !ok {
+		return ErrInvalidArgument
+	}
+	if err := codeToError(int(C.nomad_db_set_io_priority(db.ptr, cp))); err != nil {
+		return err
+	}
+	db.ioPrio.Store(int32(priority))
+	return nil
+}
+
+// maintenancePriority returns the priority background passes should run
+// at: Options.GCIOPriority when configured, otherwise idle.
+func (db *DB) maintenancePriority() IOPriority {
+	if p := IOPriority(db.gcIOPrio); p != 0 {
+		return p
+	}
+	return IOPriorityIdle
+}
+
+// lowerIOPriority drops to the maintenance priority and returns a function
+// restoring the previous setting. Failures are logged, not fatal: priority
+// is an optimization, never a correctness requirement.
+func (db *DB) lowerIOPriority() func() {
+	if db.ptr == nil || !db.IsOnDisk() {
+		return func() {}
+	}
+	prev := IOPriority(db.ioPrio.Load())
+	if err := db.SetIOPriority(db.maintenancePriority()); err != nil {
+		if err != ErrNotSupported {
+			db.log().Error("lowering I/O priority failed", "error", err)
+		}
+		return func() {}
+	}
+	return func() {
+		if prev == 0 {
+			prev = IOPriorityNormal
+		}
+		if err := db.SetIOPriority(prev); err != nil && err != ErrNotSupported {
+			db.log().Error("restoring I/O priority failed", "error", err)
+		}
+	}
+}
+
+// SetCPUAffinity pins the database's I/O threads to the given CPUs, for
+// NUMA-aware deployments where the io_uring completion threads should run
+// on the node closest to the NVMe device. Linux only; other platforms
+// return ErrNotSupported and the call is otherwise a no-op.
+func (db *DB) SetCPUAffinity(cpuMask []int) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	if len(cpuMask) == 0 {
+		return ErrInvalidArgument
+	}
+
+	cpus := make([]C.int, len(cpuMask))
+	for i, cpu := range cpuMask {
+		cpus[i] = C.int(cpu)
+	}
+	return codeToError(int(C.nomad_db_set_cpu_affinity(db.ptr, &cpus[0], C.size_t(len(cpus)))))
+}
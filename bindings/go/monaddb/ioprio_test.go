@@ -0,0 +1,51 @@
+package monaddb
+
+import "testing"
+
+// TestSetIOPriority tests priority requests and argument validation.
+func TestSetIOPriority(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/ioprio.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	// ioprio_set may be denied by the environment; both outcomes are
+	// acceptable, only a crash or a wrong error would be a bug
+	if err := db.SetIOPriority(IOPriorityIdle); err != nil && err != ErrIO {
+		t.Errorf("SetIOPriority(idle) returned unexpected error: %v", err)
+	}
+	if err := db.SetIOPriority(IOPriority(42)); err != ErrInvalidArgument {
+		t.Errorf("Invalid priority should return ErrInvalidArgument, got %v", err)
+	}
+
+	// Writes still work with a priority applied
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Errorf("Put after SetIOPriority failed: %v", err)
+	}
+}
+
+// TestSetCPUAffinity tests that pinned databases keep operating.
+func TestSetCPUAffinity(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/affinity.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetCPUAffinity([]int{0}); err != nil {
+		t.Fatalf("SetCPUAffinity failed: %v", err)
+	}
+	if err := db.SetCPUAffinity(nil); err != ErrInvalidArgument {
+		t.Errorf("Empty mask should return ErrInvalidArgument, got %v", err)
+	}
+
+	// Basic operations still succeed while pinned
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put after SetCPUAffinity failed: %v", err)
+	}
+	if _, err := db.FindFromRoot(root, makeKey32(0x01), 1); err != nil {
+		t.Errorf("FindFromRoot after SetCPUAffinity failed: %v", err)
+	}
+}
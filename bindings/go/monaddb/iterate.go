@@ -0,0 +1,233 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// goBytes copies a NomadBytes result into a Go slice and frees the C memory.
+// Returns nil if the C side produced no data.
+func goBytes(bytes *C.NomadBytes) []byte {
+	if bytes.data == nil || bytes.len == 0 {
+		return nil
+	}
+	result := C.GoBytes(unsafe.Pointer(bytes.data), C.int(bytes.len))
+	C.nomad_bytes_free(bytes)
+	return result
+}
+
+// kvEntry is one entry yielded during a trie traversal.
+type kvEntry struct {
+	Key     []byte
+	Value   []byte
+	Version uint64
+}
+
+// traversePageSize bounds how many entries are collected per C call so
+// arbitrarily large tries can be read without unbounded C allocations.
+const traversePageSize = 4096
+
+// forEach iterates all entries under prefix in ascending key order,
+// calling fn for each one. fn returns false to stop the iteration early.
+// A nil root is treated as an empty trie.
+func (db *DB) forEach(root *Node, prefix []byte, version uint64, fn func(kv kvEntry) (bool, error)) error {
+	if db.ptr == nil {
+		return ErrNullPointer
+	}
+	if root == nil || root.ptr == nil {
+		return nil // Empty trie
+	}
+
+	var prefixPtr *C.uint8_t
+	if len(prefix) > 0 {
+		prefixPtr = (*C.uint8_t)(unsafe.Pointer(&prefix[0]))
+	}
+
+	var startAfter []byte
+	for {
+		var afterPtr *C.uint8_t
+		if startAfter != nil {
+			afterPtr = (*C.uint8_t)(unsafe.Pointer(&startAfter[0]))
+		}
+
+		var list C.NomadKVList
+		code := C.nomad_db_traverse_sync(
+			db.ptr,
+			root.ptr,
+			prefixPtr,
+			C.size_t(len(prefix)),
+			afterPtr,
+			C.size_t(len(startAfter)),
+			C.size_t(traversePageSize),
+			C.uint64_t(version),
+			&list,
+		)
+		if err := codeToError(int(code)); err != nil {
+			return err
+		}
+
+		n := int(list.len)
+		if n > 0 {
+			items := unsafe.Slice(list.items, n)
+			for i := range items {
+				kv := kvEntry{
+					Version: uint64(items[i].version),
+				}
+				if items[i].key.data != nil && items[i].key.len > 0 {
+					kv.Key = C.GoBytes(unsafe.Pointer(items[i].key.data), C.int(items[i].key.len))
+				}
+				if items[i].value.data != nil && items[i].value.len > 0 {
+					raw := C.GoBytes(unsafe.Pointer(items[i].value.data), C.int(items[i].value.len))
+					value, err := db.decodeValue(raw)
+					if err != nil {
+						C.nomad_kv_list_free(&list)
+						return err
+					}
+					kv.Value = value
+				}
+
+				cont, err := fn(kv)
+				if err != nil || !cont {
+					C.nomad_kv_list_free(&list)
+					return err
+				}
+				startAfter = kv.Key
+			}
+		}
+		C.nomad_kv_list_free(&list)
+
+		if n < traversePageSize {
+			return nil // Final page
+		}
+	}
+}
+
+// CountKeysInVersion returns the number of keys stored at a version.
+//
+// The count is computed by a full trie scan, so exact is always true;
+// the flag is kept so callers can distinguish a future metadata-backed
+// O(1) implementation from the scanning one.
+func (db *DB) CountKeysInVersion(version uint64) (count uint64, exact bool, err error) {
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		return 0, false, err
+	}
+	if root == nil {
+		return 0, true, nil
+	}
+
+	err = db.forEach(root, nil, version, func(kvEntry) (bool, error) {
+		count++
+		return true, nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
+}
+
+// FirstKey returns the lexicographically smallest key stored in the trie.
+// Returns nil, nil for an empty trie.
+//
+// The C layer descends only the leftmost branch, so this is O(depth)
+// rather than a full scan.
+func (db *DB) FirstKey(root *Node, version uint64) ([]byte, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if root == nil || root.ptr == nil {
+		return nil, nil // Empty trie
+	}
+
+	var key C.NomadBytes
+	code := C.nomad_db_first_key(db.ptr, root.ptr, C.uint64_t(version), &key)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+
+	return goBytes(&key), nil
+}
+
+// RootIsEmpty reports whether the trie rooted at root holds no entries.
+// A nil root is the canonical empty trie; a non-nil root — as left behind
+// by a series of deletes — is checked with a leftmost descent, so this is
+// O(depth) rather than the full scan a key count would take. Errors during
+// the descent report the trie as non-empty, the conservative answer.
+func (db *DB) RootIsEmpty(root *Node) bool {
+	if root == nil || root.ptr == nil {
+		return true
+	}
+	key, err := db.FirstKey(root, db.LatestVersion())
+	return err == nil && key == nil
+}
+
+// LastKey returns the lexicographically largest key stored in the trie.
+// Returns nil, nil for an empty trie.
+//
+// The C layer descends only the rightmost branch, so this is O(depth)
+// rather than a full scan.
+func (db *DB) LastKey(root *Node, version uint64) ([]byte, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if root == nil || root.ptr == nil {
+		return nil, nil // Empty trie
+	}
+
+	var key C.NomadBytes
+	code := C.nomad_db_last_key(db.ptr, root.ptr, C.uint64_t(version), &key)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+
+	return goBytes(&key), nil
+}
+
+// NextKey returns the smallest key strictly greater than key.
+// Returns nil, nil if no such key exists. The reference key itself need
+// not be present in the trie.
+//
+// Together with PrevKey this underpins range proof construction: to prove
+// a key is absent, show that PrevKey and NextKey exist with no key in
+// between.
+func (db *DB) NextKey(root *Node, key []byte, version uint64) ([]byte, error) {
+	return db.boundKey(root, key, version, true)
+}
+
+// PrevKey returns the largest key strictly less than key.
+// Returns nil, nil if no such key exists. The reference key itself need
+// not be present in the trie.
+func (db *DB) PrevKey(root *Node, key []byte, version uint64) ([]byte, error) {
+	return db.boundKey(root, key, version, false)
+}
+
+func (db *DB) boundKey(root *Node, key []byte, version uint64, forward bool) ([]byte, error) {
+	if db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if root == nil || root.ptr == nil {
+		return nil, nil // Empty trie
+	}
+
+	var keyPtr *C.uint8_t
+	if len(key) > 0 {
+		keyPtr = (*C.uint8_t)(unsafe.Pointer(&key[0]))
+	}
+
+	var out C.NomadBytes
+	var code C.NomadError
+	if forward {
+		code = C.nomad_db_next_key(db.ptr, root.ptr, keyPtr, C.size_t(len(key)), C.uint64_t(version), &out)
+	} else {
+		code = C.nomad_db_prev_key(db.ptr, root.ptr, keyPtr, C.size_t(len(key)), C.uint64_t(version), &out)
+	}
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+
+	return goBytes(&out), nil
+}
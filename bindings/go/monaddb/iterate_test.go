@@ -0,0 +1,159 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestFirstAndLastKey tests edge-key navigation.
+func TestFirstAndLastKey(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Type: UpdatePut, Key: makeKey32(0x20), Value: []byte("middle")},
+		{Type: UpdatePut, Key: makeKey32(0x05), Value: []byte("low")},
+		{Type: UpdatePut, Key: makeKey32(0xF0), Value: []byte("high")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	first, err := db.FirstKey(root, 1)
+	if err != nil {
+		t.Fatalf("FirstKey failed: %v", err)
+	}
+	if !bytes.Equal(first, makeKey32(0x05)) {
+		t.Errorf("FirstKey mismatch: got %x", first)
+	}
+
+	last, err := db.LastKey(root, 1)
+	if err != nil {
+		t.Fatalf("LastKey failed: %v", err)
+	}
+	if !bytes.Equal(last, makeKey32(0xF0)) {
+		t.Errorf("LastKey mismatch: got %x", last)
+	}
+}
+
+// TestNextAndPrevKey tests ordered trie navigation.
+func TestNextAndPrevKey(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Type: UpdatePut, Key: makeKey32(0x10), Value: []byte("a")},
+		{Type: UpdatePut, Key: makeKey32(0x20), Value: []byte("b")},
+		{Type: UpdatePut, Key: makeKey32(0x30), Value: []byte("c")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	// NextKey from an existing key
+	next, err := db.NextKey(root, makeKey32(0x10), 1)
+	if err != nil {
+		t.Fatalf("NextKey failed: %v", err)
+	}
+	if !bytes.Equal(next, makeKey32(0x20)) {
+		t.Errorf("NextKey(0x10) mismatch: got %x", next)
+	}
+
+	// NextKey from a key that does not exist
+	next, err = db.NextKey(root, makeKey32(0x15), 1)
+	if err != nil {
+		t.Fatalf("NextKey failed: %v", err)
+	}
+	if !bytes.Equal(next, makeKey32(0x20)) {
+		t.Errorf("NextKey(0x15) mismatch: got %x", next)
+	}
+
+	// NextKey past the last key
+	next, err = db.NextKey(root, makeKey32(0x30), 1)
+	if err != nil {
+		t.Fatalf("NextKey failed: %v", err)
+	}
+	if next != nil {
+		t.Errorf("NextKey past end should be nil, got %x", next)
+	}
+
+	// PrevKey
+	prev, err := db.PrevKey(root, makeKey32(0x30), 1)
+	if err != nil {
+		t.Fatalf("PrevKey failed: %v", err)
+	}
+	if !bytes.Equal(prev, makeKey32(0x20)) {
+		t.Errorf("PrevKey(0x30) mismatch: got %x", prev)
+	}
+
+	// PrevKey before the first key
+	prev, err = db.PrevKey(root, makeKey32(0x10), 1)
+	if err != nil {
+		t.Fatalf("PrevKey failed: %v", err)
+	}
+	if prev != nil {
+		t.Errorf("PrevKey before start should be nil, got %x", prev)
+	}
+}
+
+// TestFirstKeyEmptyTrie tests that an empty trie yields nil keys.
+func TestFirstKeyEmptyTrie(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	first, err := db.FirstKey(nil, 1)
+	if err != nil {
+		t.Fatalf("FirstKey failed: %v", err)
+	}
+	if first != nil {
+		t.Errorf("FirstKey on empty trie should be nil, got %x", first)
+	}
+
+	last, err := db.LastKey(nil, 1)
+	if err != nil {
+		t.Fatalf("LastKey failed: %v", err)
+	}
+	if last != nil {
+		t.Errorf("LastKey on empty trie should be nil, got %x", last)
+	}
+}
+
+// TestRootIsEmpty tests empty detection before and after deletes.
+func TestRootIsEmpty(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if !db.RootIsEmpty(nil) {
+		t.Error("Nil root should be empty")
+	}
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if db.RootIsEmpty(root) {
+		t.Error("Populated trie should not be empty")
+	}
+
+	deleted, err := db.Delete(root, makeKey32(0x01), 2)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if !db.RootIsEmpty(deleted) {
+		t.Error("Trie should be empty after deleting its only key")
+	}
+}
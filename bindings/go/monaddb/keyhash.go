@@ -0,0 +1,47 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import "unsafe"
+
+// KeyHash applies the database's key encoder (keccak-256) to derive the
+// 32-byte trie key from an application-level input, such as an account
+// address or a storage slot preimage.
+//
+// Storing under hashed keys is the expected pattern:
+//
+//	root, err := db.Put(nil, db.KeyHash(accountAddr), value, version)
+func (db *DB) KeyHash(input []byte) [32]byte {
+	var hash [32]byte
+
+	var inputPtr *C.uint8_t
+	if len(input) > 0 {
+		inputPtr = (*C.uint8_t)(unsafe.Pointer(&input[0]))
+	}
+	C.nomad_keccak256(inputPtr, C.size_t(len(input)), (*C.uint8_t)(unsafe.Pointer(&hash[0])))
+	return hash
+}
+
+// AddressToKey derives the account trie key for an address:
+// keccak256(address). Centralizing the derivation keeps integrations from
+// hand-rolling it with the wrong padding or hash width.
+func AddressToKey(addr [20]byte) [32]byte {
+	var hash [32]byte
+	C.nomad_keccak256((*C.uint8_t)(unsafe.Pointer(&addr[0])), C.size_t(len(addr)), (*C.uint8_t)(unsafe.Pointer(&hash[0])))
+	return hash
+}
+
+// StorageSlotKey derives the storage trie key for one slot of a contract:
+// keccak256(pad32(address) || slot), with the address left-padded to 32
+// bytes per the EVM storage layout.
+func StorageSlotKey(addr [20]byte, slot [32]byte) [32]byte {
+	var preimage [64]byte
+	copy(preimage[12:32], addr[:])
+	copy(preimage[32:], slot[:])
+
+	var hash [32]byte
+	C.nomad_keccak256((*C.uint8_t)(unsafe.Pointer(&preimage[0])), C.size_t(len(preimage)), (*C.uint8_t)(unsafe.Pointer(&hash[0])))
+	return hash
+}
@@ -0,0 +1,77 @@
+package monaddb
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestKeyHash tests that KeyHash matches keccak256 for a known address.
+func TestKeyHash(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	// keccak256 of the zero address, a well-known Ethereum test vector
+	addr := make([]byte, 20)
+	want := "5380c7b7ae81a58eb98d9c78de4a1fd7fd9535fc953ed2be602daaa41767312a"
+
+	hash := db.KeyHash(addr)
+	if got := hex.EncodeToString(hash[:]); got != want {
+		t.Errorf("KeyHash mismatch:\n  got  %s\n  want %s", got, want)
+	}
+
+	// keccak256 of the empty input
+	empty := db.KeyHash(nil)
+	wantEmpty := "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+	if got := hex.EncodeToString(empty[:]); got != wantEmpty {
+		t.Errorf("KeyHash(nil) mismatch:\n  got  %s\n  want %s", got, wantEmpty)
+	}
+}
+
+// TestAddressToKey tests that account keys match keccak256 of the address.
+func TestAddressToKey(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var addr [20]byte
+	addr[19] = 0x01
+
+	key := AddressToKey(addr)
+	if want := db.KeyHash(addr[:]); key != want {
+		t.Errorf("AddressToKey should equal keccak256 of the address:\n  got  %x\n  want %x", key, want)
+	}
+}
+
+// TestStorageSlotKey tests the padded storage-slot preimage.
+func TestStorageSlotKey(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var addr [20]byte
+	addr[19] = 0x01
+	var slot [32]byte
+	slot[31] = 0x02
+
+	var preimage [64]byte
+	copy(preimage[12:32], addr[:])
+	copy(preimage[32:], slot[:])
+
+	key := StorageSlotKey(addr, slot)
+	if want := db.KeyHash(preimage[:]); key != want {
+		t.Errorf("StorageSlotKey should hash the padded preimage:\n  got  %x\n  want %x", key, want)
+	}
+
+	var other [32]byte
+	other[31] = 0x03
+	if StorageSlotKey(addr, other) == key {
+		t.Error("Distinct slots should derive distinct keys")
+	}
+}
@@ -0,0 +1,170 @@
+package monaddb
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyHistogram summarizes the latency distribution of an operation
+// class. Percentile values are bucket lower bounds, so they are slightly
+// pessimistic-to-exact depending on bucket resolution.
+type LatencyHistogram struct {
+	P50   time.Duration
+	P75   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	P999  time.Duration
+	Count uint64
+}
+
+// latencyBuckets is the number of histogram buckets: 64 exponents with
+// 4 linear sub-buckets each (HDR-style, ~25% relative error).
+const latencyBuckets = 64 * 4
+
+// latencyRecorder is a lock-free log-bucketed histogram. Recording is a
+// single atomic increment, keeping per-operation overhead well under
+// 100 ns.
+type latencyRecorder struct {
+	counts [latencyBuckets]atomic.Uint64
+	total  atomic.Uint64
+}
+
+// latencyBucket maps a duration to its bucket index.
+func latencyBucket(d time.Duration) int {
+	ns := uint64(d)
+	if ns < 4 {
+		return int(ns)
+	}
+	exp := bits.Len64(ns) - 1      // position of the leading bit, >= 2
+	sub := (ns >> (exp - 2)) & 0x3 // next two bits below the leading bit
+	return (exp-1)*4 + int(sub)
+}
+
+// latencyBucketValue returns the lower bound duration of a bucket.
+func latencyBucketValue(idx int) time.Duration {
+	if idx < 4 {
+		return time.Duration(idx)
+	}
+	exp := idx/4 + 1
+	sub := uint64(idx % 4)
+	return time.Duration((uint64(1) << exp) | (sub << (exp - 2)))
+}
+
+func (r *latencyRecorder) record(d time.Duration) {
+	r.counts[latencyBucket(d)].Add(1)
+	r.total.Add(1)
+}
+
+// histogram computes percentile values from the current bucket counts.
+func (r *latencyRecorder) histogram() LatencyHistogram {
+	var counts [latencyBuckets]uint64
+	var total uint64
+	for i := range r.counts {
+		counts[i] = r.counts[i].Load()
+		total += counts[i]
+	}
+
+	h := LatencyHistogram{Count: total}
+	if total == 0 {
+		return h
+	}
+
+	percentile := func(p float64) time.Duration {
+		target := uint64(p * float64(total))
+		if target == 0 {
+			target = 1
+		}
+		var seen uint64
+		for i := range counts {
+			seen += counts[i]
+			if seen >= target {
+				return latencyBucketValue(i)
+			}
+		}
+		return latencyBucketValue(latencyBuckets - 1)
+	}
+
+	h.P50 = percentile(0.50)
+	h.P75 = percentile(0.75)
+	h.P95 = percentile(0.95)
+	h.P99 = percentile(0.99)
+	h.P999 = percentile(0.999)
+	return h
+}
+
+// slowOpConfig pairs a latency threshold with the callback to fire when an
+// operation exceeds it. Swapped atomically so readers never lock.
+type slowOpConfig struct {
+	threshold time.Duration
+	cb        func(op string, key []byte, duration time.Duration)
+}
+
+// SetSlowOpThreshold installs a callback that fires whenever a Find, Put,
+// Upsert or other timed operation takes longer than threshold. The callback
+// receives the operation name, the key involved (the first key for batch
+// operations, nil for empty batches) and the measured duration.
+//
+// The check lives entirely in the completion path and only fires for slow
+// operations, so fast-path overhead is a single atomic load. Passing a
+// threshold <= 0 or a nil callback disables reporting.
+func (db *DB) SetSlowOpThreshold(threshold time.Duration, cb func(op string, key []byte, duration time.Duration)) {
+	if threshold <= 0 || cb == nil {
+		db.slowOp.Store(nil)
+		return
+	}
+	db.slowOp.Store(&slowOpConfig{threshold: threshold, cb: cb})
+}
+
+// finishOp records a completed operation in the histogram and reports it
+// if it exceeded the slow-op or slow-log thresholds.
+func (db *DB) finishOp(rec *latencyRecorder, op string, key []byte, version uint64, start time.Time) {
+	elapsed := time.Since(start)
+	rec.record(elapsed)
+	if cfg := db.slowOp.Load(); cfg != nil && elapsed > cfg.threshold {
+		cfg.cb(op, key, elapsed)
+	}
+	if cfg := db.slowLog.Load(); cfg != nil && elapsed > cfg.threshold {
+		cfg.log(op, key, version, elapsed)
+	}
+	if mp := db.metrics.Load(); mp != nil {
+		(*mp).ObserveOp(op, elapsed)
+	}
+}
+
+// span opens a tracer span for op; used as `defer db.span(op)()`. A no-op
+// when no tracer is installed.
+func (db *DB) span(op string) func() {
+	if tp := db.tracer.Load(); tp != nil {
+		return (*tp).StartSpan(op)
+	}
+	return func() {}
+}
+
+// timeRead records a completed read; used as `defer db.timeRead(op, key, version, time.Now())`.
+func (db *DB) timeRead(op string, key []byte, version uint64, start time.Time) {
+	db.finishOp(&db.readLat, op, key, version, start)
+}
+
+// timeWrite records a completed write; used as `defer db.timeWrite(op, key, version, time.Now())`.
+func (db *DB) timeWrite(op string, key []byte, version uint64, start time.Time) {
+	db.finishOp(&db.writeLat, op, key, version, start)
+}
+
+// ReadLatency returns the latency histogram for read operations
+// (Find and FindFromRoot).
+func (db *DB) ReadLatency() (LatencyHistogram, error) {
+	if db == nil || db.ptr == nil {
+		return LatencyHistogram{}, ErrNullPointer
+	}
+	return db.readLat.histogram(), nil
+}
+
+// WriteLatency returns the latency histogram for write operations
+// (Upsert and its convenience wrappers).
+func (db *DB) WriteLatency() (LatencyHistogram, error) {
+	if db == nil || db.ptr == nil {
+		return LatencyHistogram{}, ErrNullPointer
+	}
+	return db.writeLat.histogram(), nil
+}
@@ -0,0 +1,99 @@
+package monaddb
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLatencyBucketRoundTrip tests that bucket values stay close to the
+// original durations.
+func TestLatencyBucketRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{
+		0, 1, 100, time.Microsecond, 50 * time.Microsecond,
+		time.Millisecond, 150 * time.Millisecond, time.Second,
+	} {
+		idx := latencyBucket(d)
+		lower := latencyBucketValue(idx)
+		if lower > d {
+			t.Errorf("Bucket lower bound %v exceeds recorded duration %v", lower, d)
+		}
+		if d > 4 && lower < d/2 {
+			t.Errorf("Bucket lower bound %v too far below %v", lower, d)
+		}
+	}
+}
+
+// TestSlowOpThreshold tests the slow-operation callback.
+func TestSlowOpThreshold(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var fired int
+	var lastOp string
+	// A negative-duration threshold can't be set, so use 1ns and rely on
+	// any real operation taking longer
+	db.SetSlowOpThreshold(time.Nanosecond, func(op string, key []byte, d time.Duration) {
+		fired++
+		lastOp = op
+	})
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if fired == 0 {
+		t.Error("Slow-op callback should have fired for a 1ns threshold")
+	}
+	if lastOp != "upsert" {
+		t.Errorf("Slow-op callback op should be %q, got %q", "upsert", lastOp)
+	}
+
+	// Disabling stops further callbacks
+	db.SetSlowOpThreshold(0, nil)
+	before := fired
+	if _, err := db.Put(nil, makeKey32(0x02), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if fired != before {
+		t.Error("Slow-op callback should not fire after being disabled")
+	}
+}
+
+// TestReadWriteLatency tests that operations populate the histograms.
+func TestReadWriteLatency(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	root, err := db.Put(nil, key, []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := db.FindFromRoot(root, key, 1); err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+
+	read, err := db.ReadLatency()
+	if err != nil {
+		t.Fatalf("ReadLatency failed: %v", err)
+	}
+	if read.Count != 1 {
+		t.Errorf("Read count should be 1, got %d", read.Count)
+	}
+
+	write, err := db.WriteLatency()
+	if err != nil {
+		t.Fatalf("WriteLatency failed: %v", err)
+	}
+	if write.Count != 1 {
+		t.Errorf("Write count should be 1, got %d", write.Count)
+	}
+	if write.P99 < write.P50 {
+		t.Errorf("P99 (%v) should not be below P50 (%v)", write.P99, write.P50)
+	}
+}
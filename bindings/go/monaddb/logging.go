@@ -0,0 +1,52 @@
+package monaddb
+
+import "log/slog"
+
+// Logger receives diagnostic output from the library. Messages carry
+// alternating key/value fields in the slog style. Implementations must be
+// safe for concurrent use.
+type Logger interface {
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Error(msg string, fields ...any)
+}
+
+// NopLogger discards all log output. It is the default.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any) {}
+func (NopLogger) Info(string, ...any)  {}
+func (NopLogger) Error(string, ...any) {}
+
+// SlogLogger adapts a *slog.Logger to the Logger interface, routing the
+// library's output into standard Go application logging.
+func SlogLogger(l *slog.Logger) Logger {
+	return slogAdapter{l}
+}
+
+type slogAdapter struct {
+	l *slog.Logger
+}
+
+func (a slogAdapter) Debug(msg string, fields ...any) { a.l.Debug(msg, fields...) }
+func (a slogAdapter) Info(msg string, fields ...any)  { a.l.Info(msg, fields...) }
+func (a slogAdapter) Error(msg string, fields ...any) { a.l.Error(msg, fields...) }
+
+// SetLogger installs a logger for lifecycle events: close, batch flushes,
+// version pruning, and background maintenance. Passing nil restores the
+// default NopLogger. Safe to call concurrently with other operations.
+func (db *DB) SetLogger(l Logger) {
+	if l == nil {
+		db.logger.Store(nil)
+		return
+	}
+	db.logger.Store(&l)
+}
+
+// log returns the installed logger, or NopLogger when none is set.
+func (db *DB) log() Logger {
+	if lp := db.logger.Load(); lp != nil {
+		return *lp
+	}
+	return NopLogger{}
+}
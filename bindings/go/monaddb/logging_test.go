@@ -0,0 +1,50 @@
+package monaddb
+
+import (
+	"sync"
+	"testing"
+)
+
+// recordingLogger captures log messages for assertions.
+type recordingLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *recordingLogger) record(msg string) {
+	r.mu.Lock()
+	r.msgs = append(r.msgs, msg)
+	r.mu.Unlock()
+}
+
+func (r *recordingLogger) Debug(msg string, fields ...any) { r.record(msg) }
+func (r *recordingLogger) Info(msg string, fields ...any)  { r.record(msg) }
+func (r *recordingLogger) Error(msg string, fields ...any) { r.record(msg) }
+
+// TestSetLogger tests that lifecycle events reach the installed logger.
+func TestSetLogger(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+
+	rec := &recordingLogger{}
+	db.SetLogger(rec)
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	db.Close()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	found := false
+	for _, msg := range rec.msgs {
+		if msg == "database closed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Close should log a lifecycle event, got %v", rec.msgs)
+	}
+}
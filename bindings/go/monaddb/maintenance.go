@@ -0,0 +1,145 @@
+package monaddb
+
+import (
+	"context"
+	"fmt"
+)
+
+// defragBatchSize is the number of key-value pairs rewritten per Upsert
+// during Defragment. Large enough to amortize the C call, small enough
+// to keep cancellation responsive.
+const defragBatchSize = 1024
+
+// Defragment rewrites the latest version of the trie with nodes laid out
+// in depth-first key order. After heavy random writes the on-disk pages
+// holding adjacent keys can be scattered; rewriting the whole trie in one
+// sequential pass restores locality, so prefix scans are again served from
+// sequentially-laid-out blocks.
+//
+// The pass streams every key-value pair in key order and re-upserts them
+// in batches at the latest version. The state root is verified to be
+// unchanged afterwards; a mismatch is reported as an error and indicates
+// a bug or concurrent writes during the pass.
+//
+// Defragment honours ctx between batches, so a long pass can be aborted.
+// Do not write concurrently while it runs.
+func (db *DB) Defragment(ctx context.Context) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	defer db.lowerIOPriority()()
+
+	version := db.LatestVersion()
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return nil // empty database, nothing to lay out
+	}
+	originalHash, err := root.Hash()
+	if err != nil {
+		return err
+	}
+
+	newRoot := root
+	batch := make([]Update, 0, defragBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		r, err := db.Upsert(newRoot, batch, version)
+		if err != nil {
+			return err
+		}
+		newRoot = r
+		batch = batch[:0]
+		return nil
+	}
+
+	err = db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		batch = append(batch, Update{Type: UpdatePut, Key: kv.Key, Value: kv.Value})
+		if len(batch) < defragBatchSize {
+			return true, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		return true, flush()
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	newHash, err := newRoot.Hash()
+	if err != nil {
+		return err
+	}
+	if newHash != originalHash {
+		return fmt.Errorf("monaddb: defragment changed state root %x -> %x: %w",
+			originalHash, newHash, ErrInternal)
+	}
+	return nil
+}
+
+// Checkpoint copies the current consistent state into a standalone
+// database file at destPath, readable by OpenDisk without any recovery
+// step. Only the latest version is materialized in the copy; history that
+// has already been pruned from the source is naturally absent. Reads on
+// the source proceed normally while the checkpoint streams — the pass
+// never takes a write lock, it only traverses the latest root.
+//
+// This differs from archiving a version externally: the result is a live
+// database file that can be opened and written immediately, which makes
+// it suitable for seeding replicas or taking pre-upgrade snapshots.
+func (db *DB) Checkpoint(destPath string) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	defer db.lowerIOPriority()()
+
+	dest, err := OpenDisk(destPath, true, db.HistoryLength())
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	version := db.LatestVersion()
+	root, err := db.LoadRoot(version)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	var destRoot *Node
+	batch := make([]Update, 0, defragBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		r, err := dest.Upsert(destRoot, batch, version)
+		if err != nil {
+			return err
+		}
+		destRoot = r
+		batch = batch[:0]
+		return nil
+	}
+
+	err = db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		batch = append(batch, Update{Type: UpdatePut, Key: kv.Key, Value: kv.Value})
+		if len(batch) < defragBatchSize {
+			return true, nil
+		}
+		return true, flush()
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return dest.Sync()
+}
@@ -0,0 +1,111 @@
+package monaddb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// TestDefragment tests that a defragmentation pass preserves the state root.
+func TestDefragment(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/defrag.db", true, 100)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for i := byte(0); i < 50; i++ {
+		root, err = db.Put(root, makeKey32(i), []byte{i}, 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	before, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+
+	if err := db.Defragment(context.Background()); err != nil {
+		t.Fatalf("Defragment failed: %v", err)
+	}
+
+	after, err := db.LoadRoot(db.LatestVersion())
+	if err != nil {
+		t.Fatalf("LoadRoot failed: %v", err)
+	}
+	afterHash, err := after.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if afterHash != before {
+		t.Errorf("State root changed: %x -> %x", before, afterHash)
+	}
+}
+
+// TestDefragmentCancel tests that a cancelled context aborts the pass.
+func TestDefragmentCancel(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/defrag.db", true, 100)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for i := 0; i < 2*defragBatchSize; i++ {
+		key := makeKey32(byte(i))
+		key[0] = byte(i >> 8)
+		root, err = db.Put(root, key, []byte("v"), 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := db.Defragment(ctx); err != context.Canceled {
+		t.Errorf("Defragment should return context.Canceled, got %v", err)
+	}
+}
+
+// TestCheckpoint tests that the copy opens standalone with the same state.
+func TestCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	db, err := OpenDisk(dir+"/source.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for i := byte(1); i <= 5; i++ {
+		root, err = db.Put(root, makeKey32(i), []byte{'v', i}, 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	if err := db.Checkpoint(dir + "/checkpoint.db"); err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+
+	ck, err := OpenDisk(dir+"/checkpoint.db", false, 10)
+	if err != nil {
+		t.Fatalf("Checkpoint should open standalone: %v", err)
+	}
+	defer ck.Close()
+
+	for i := byte(1); i <= 5; i++ {
+		node, err := ck.Find(makeKey32(i), 1)
+		if err != nil {
+			t.Fatalf("Find in checkpoint failed: %v", err)
+		}
+		value, err := node.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if !bytes.Equal(value, []byte{'v', i}) {
+			t.Errorf("Checkpoint value mismatch for key %d: got %q", i, value)
+		}
+	}
+}
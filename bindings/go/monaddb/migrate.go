@@ -0,0 +1,81 @@
+package monaddb
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// formatVersion is the on-disk schema version this library writes. It is
+// recorded in a sidecar file next to the database so a future library
+// release can detect databases created by an older one.
+const formatVersion = 1
+
+// formatVersionPath returns the sidecar file recording the schema version.
+func formatVersionPath(path string) string {
+	return path + ".format"
+}
+
+// readFormatVersion reads the recorded schema version, defaulting to the
+// current one for databases that predate the sidecar file.
+func readFormatVersion(path string) (uint64, error) {
+	data, err := os.ReadFile(formatVersionPath(path))
+	if os.IsNotExist(err) {
+		return formatVersion, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("monaddb: corrupt format version file: %w", ErrInvalidArgument)
+	}
+	return v, nil
+}
+
+// writeFormatVersion records the schema version in the sidecar file.
+func writeFormatVersion(path string, version uint64) error {
+	return os.WriteFile(formatVersionPath(path), []byte(strconv.FormatUint(version, 10)+"\n"), 0o644)
+}
+
+// OpenDiskWithMigration opens a disk database, invoking migrator when the
+// database was written by a different schema version than this library
+// uses. The migrator receives the old and new schema versions along with
+// the opened database and performs whatever rewrite the upgrade needs; if
+// it returns nil the recorded version is bumped and the open proceeds.
+// A migrator error (or a nil migrator when migration is needed) aborts the
+// open with the database closed, leaving the recorded version untouched so
+// the next attempt sees the same mismatch.
+//
+// This lets long-running node software upgrade its database format in
+// place on startup, without operator intervention.
+func OpenDiskWithMigration(path string, opts Options, migrator func(oldVersion, newVersion uint64, db *DB) error) (*DB, error) {
+	stored, err := readFormatVersion(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := OpenDiskWithOptions(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored != formatVersion {
+		if migrator == nil {
+			db.Close()
+			return nil, fmt.Errorf("monaddb: format version %d needs migration to %d: %w",
+				stored, formatVersion, ErrNotSupported)
+		}
+		if err := migrator(stored, formatVersion, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	if err := writeFormatVersion(path, formatVersion); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
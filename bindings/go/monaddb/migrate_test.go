@@ -0,0 +1,53 @@
+package monaddb
+
+import (
+	"os"
+	"testing"
+)
+
+// TestOpenDiskWithMigration tests schema mismatch detection and upgrade.
+func TestOpenDiskWithMigration(t *testing.T) {
+	path := t.TempDir() + "/migrate.db"
+	opts := Options{Create: true, HistoryLength: 10}
+
+	// First open creates the database and records the current format
+	db, err := OpenDiskWithMigration(path, opts, nil)
+	if err != nil {
+		t.Fatalf("OpenDiskWithMigration failed: %v", err)
+	}
+	db.Close()
+
+	// Simulate a database written by an older library
+	if err := os.WriteFile(formatVersionPath(path), []byte("0\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	// Without a migrator the mismatch is fatal
+	if _, err := OpenDiskWithMigration(path, opts, nil); err == nil {
+		t.Error("Mismatch without a migrator should fail")
+	}
+
+	// The migrator sees both versions and unblocks the open
+	var gotOld, gotNew uint64
+	db, err = OpenDiskWithMigration(path, opts, func(oldVersion, newVersion uint64, db *DB) error {
+		gotOld, gotNew = oldVersion, newVersion
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithMigration with migrator failed: %v", err)
+	}
+	db.Close()
+	if gotOld != 0 || gotNew != formatVersion {
+		t.Errorf("Migrator should see 0 -> %d, got %d -> %d", formatVersion, gotOld, gotNew)
+	}
+
+	// The recorded version was bumped, so no migration happens next time
+	db, err = OpenDiskWithMigration(path, opts, func(oldVersion, newVersion uint64, db *DB) error {
+		t.Error("Migrator should not run when versions match")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithMigration failed: %v", err)
+	}
+	db.Close()
+}
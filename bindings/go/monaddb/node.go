@@ -15,6 +15,9 @@ import (
 // Nodes are reference-counted and must be freed when no longer needed.
 type Node struct {
 	ptr *C.NomadNode
+
+	// Owning database, used to decode values written through its codec
+	db *DB
 }
 
 // Free releases the node's resources.
@@ -37,11 +40,54 @@ func (n *Node) Clone() *Node {
 		return nil
 	}
 	
-	clone := &Node{ptr: clonePtr}
+	clone := &Node{ptr: clonePtr, db: n.db}
 	runtime.SetFinalizer(clone, (*Node).Free)
 	return clone
 }
 
+// EmptyRoot returns the canonical empty-trie root node. Its hash is the
+// well-known Ethereum empty-trie hash
+// 0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421.
+// Code paths that want an explicit root to thread through — rather than
+// the nil that Put and Upsert also accept — can start from this node.
+// Returns nil only if allocation fails.
+func (db *DB) EmptyRoot() *Node {
+	var ptr *C.NomadNode
+	code := C.nomad_node_empty(&ptr)
+	if codeToError(int(code)) != nil {
+		return nil
+	}
+
+	node := &Node{ptr: ptr, db: db}
+	runtime.SetFinalizer(node, (*Node).Free)
+	return node
+}
+
+// WithValue returns a new leaf node carrying newValue in place of this
+// node's value, keeping the same key fragment and hash lineage. The
+// receiver is untouched — roots referencing it still observe the old
+// value — making this the immutable-update building block.
+func (n *Node) WithValue(newValue []byte) (*Node, error) {
+	if n == nil || n.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var valuePtr *C.uint8_t
+	if len(newValue) > 0 {
+		valuePtr = (*C.uint8_t)(unsafe.Pointer(&newValue[0]))
+	}
+
+	var nodePtr *C.NomadNode
+	code := C.nomad_node_with_value(n.ptr, valuePtr, C.size_t(len(newValue)), &nodePtr)
+	if err := codeToError(int(code)); err != nil {
+		return nil, err
+	}
+
+	fresh := &Node{ptr: nodePtr, db: n.db}
+	runtime.SetFinalizer(fresh, (*Node).Free)
+	return fresh, nil
+}
+
 // HasValue returns true if the node has a value.
 func (n *Node) HasValue() bool {
 	if n.ptr == nil {
@@ -72,10 +118,19 @@ func (n *Node) Value() ([]byte, error) {
 	
 	// Copy data to Go slice
 	result := C.GoBytes(unsafe.Pointer(bytes.data), C.int(bytes.len))
-	
+
 	// Free C memory
 	C.nomad_bytes_free(&bytes)
-	
+
+	// Transparently decompress values written through the owning
+	// database's codec
+	if n.db != nil {
+		decoded, err := n.db.decodeValue(result)
+		if err != nil {
+			return nil, err
+		}
+		return n.db.maybeCorrupt(decoded), nil
+	}
 	return result, nil
 }
 
@@ -0,0 +1,79 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestNodeWithValue tests the immutable value replacement.
+func TestNodeWithValue(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("old"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	leaf, err := db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+
+	fresh, err := leaf.WithValue([]byte("new"))
+	if err != nil {
+		t.Fatalf("WithValue failed: %v", err)
+	}
+	value, err := fresh.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("new")) {
+		t.Errorf("New node should carry the new value, got %q", value)
+	}
+
+	// The original node and root are untouched
+	value, err = leaf.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("old")) {
+		t.Errorf("Original node should keep the old value, got %q", value)
+	}
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	value, err = node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("old")) {
+		t.Errorf("Old root should still resolve the old value, got %q", value)
+	}
+}
+
+// TestEmptyRoot tests the canonical empty-trie root hash.
+func TestEmptyRoot(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	empty := db.EmptyRoot()
+	if empty == nil {
+		t.Fatal("EmptyRoot returned nil")
+	}
+
+	hex, err := empty.HashHex()
+	if err != nil {
+		t.Fatalf("HashHex failed: %v", err)
+	}
+	const want = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	if hex != want {
+		t.Errorf("Empty root hash should be %s, got %s", want, hex)
+	}
+}
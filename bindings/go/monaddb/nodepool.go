@@ -0,0 +1,67 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import (
+	"runtime"
+	"sync"
+)
+
+// NodePool recycles Node wrappers for workloads that allocate millions of
+// short-lived nodes per block. Returning a node to the pool releases its
+// C-side resources immediately and clears the finalizer, so the usual
+// finalizer-driven cleanup (and its GC pressure) is skipped entirely for
+// pooled nodes.
+type NodePool struct {
+	pool sync.Pool
+}
+
+// NewNodePool creates a pool pre-warmed with size empty wrappers.
+func NewNodePool(size int) *NodePool {
+	p := &NodePool{
+		pool: sync.Pool{
+			New: func() any { return &Node{} },
+		},
+	}
+	for i := 0; i < size; i++ {
+		p.pool.Put(&Node{})
+	}
+	return p
+}
+
+// Get returns a Node wrapper from the pool. The wrapper starts empty
+// (no C node attached); it is typically handed to Clone-style helpers or
+// reused as scratch in tight lookup loops.
+func (p *NodePool) Get() *Node {
+	return p.pool.Get().(*Node)
+}
+
+// GetFrom clones src into a pooled wrapper, incrementing the C reference
+// count. The returned node must be released with Put rather than Free.
+func (p *NodePool) GetFrom(src *Node) *Node {
+	n := p.Get()
+	if src != nil && src.ptr != nil {
+		n.ptr = C.nomad_node_clone(src.ptr)
+		n.db = src.db
+	}
+	return n
+}
+
+// Put releases a node's C resources synchronously and recycles the Go
+// wrapper. The node must not be used after Put; passing nil is a no-op.
+func (p *NodePool) Put(n *Node) {
+	if n == nil {
+		return
+	}
+	if n.ptr != nil {
+		C.nomad_node_free(n.ptr)
+		n.ptr = nil
+	}
+	// Pooled nodes are freed explicitly above; drop any finalizer a
+	// non-pooled code path may have installed
+	runtime.SetFinalizer(n, nil)
+	n.db = nil
+	p.pool.Put(n)
+}
@@ -0,0 +1,55 @@
+package monaddb
+
+import "testing"
+
+// TestNodePool tests wrapper recycling and C node lifecycle handling.
+func TestNodePool(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	root, err := db.Put(nil, key, []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	pool := NewNodePool(4)
+
+	// A pooled clone shares the underlying C node
+	pooled := pool.GetFrom(root)
+	if pooled.ptr == nil {
+		t.Fatal("GetFrom should attach a cloned C node")
+	}
+	hash, err := pooled.Hash()
+	if err != nil {
+		t.Fatalf("Hash on pooled node failed: %v", err)
+	}
+	rootHash, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if hash != rootHash {
+		t.Error("Pooled clone should hash identically to its source")
+	}
+
+	// Put releases the C node and recycles the wrapper
+	pool.Put(pooled)
+	if pooled.ptr != nil {
+		t.Error("Put should detach the C node")
+	}
+
+	reused := pool.Get()
+	if reused.ptr != nil {
+		t.Error("Recycled wrapper should come back empty")
+	}
+
+	// The original node is unaffected by releasing the clone
+	if _, err := root.Hash(); err != nil {
+		t.Errorf("Source node should survive the clone's release: %v", err)
+	}
+
+	pool.Put(nil) // no-op
+}
@@ -71,6 +71,7 @@ typedef enum {
     NOMAD_ERR_VERSION_OUT_OF_RANGE = 5,
     NOMAD_ERR_NOT_SUPPORTED = 6,
     NOMAD_ERR_OUT_OF_MEMORY = 7,
+    NOMAD_ERR_VERSION_CONFLICT = 8,
     NOMAD_ERR_INTERNAL = 255,
 } NomadError;
 
@@ -111,13 +112,61 @@ NomadError nomad_db_open_disk(
     NomadDb** db_out
 );
 
+/**
+ * Clone an in-memory database
+ *
+ * Creates an independent database whose version history references the
+ * source's existing nodes; copy-on-write upserts keep subsequent writes on
+ * either side from affecting the other. Only supported for in-memory
+ * databases — disk databases return NOMAD_ERR_NOT_SUPPORTED.
+ *
+ * @param db Source database handle
+ * @param[out] db_out Pointer to receive the clone handle
+ * @return NOMAD_OK on success, error code otherwise
+ */
+NomadError nomad_db_clone(const NomadDb* db, NomadDb** db_out);
+
 /**
  * Close and free a database handle
- * 
+ *
  * @param db Database handle (NULL is safe)
  */
 void nomad_db_close(NomadDb* db);
 
+/** I/O scheduling priority for database threads */
+typedef enum {
+    NOMAD_IOPRIO_HIGH = 0,
+    NOMAD_IOPRIO_NORMAL = 1,
+    NOMAD_IOPRIO_IDLE = 2,
+} NomadIOPriority;
+
+/**
+ * Request an I/O scheduling priority for the database's threads
+ *
+ * Calls ioprio_set on Linux; returns NOMAD_ERR_NOT_SUPPORTED on other
+ * platforms. High and normal map to best-effort class levels 0 and 4,
+ * idle maps to the idle class.
+ *
+ * @param db Database handle
+ * @param priority Requested priority
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_set_io_priority(NomadDb* db, NomadIOPriority priority);
+
+/**
+ * Pin the database's I/O threads to a set of CPUs
+ *
+ * Applies sched_setaffinity on Linux so the io_uring completion threads
+ * run on the given CPUs — typically the NUMA node closest to the NVMe
+ * device. Returns NOMAD_ERR_NOT_SUPPORTED on other platforms.
+ *
+ * @param db Database handle
+ * @param cpus Array of CPU indices
+ * @param num_cpus Number of entries in cpus (must be > 0)
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_set_cpu_affinity(NomadDb* db, const int* cpus, size_t num_cpus);
+
 /**
  * Check if database is using disk storage
  */
@@ -187,6 +236,44 @@ NomadError nomad_node_get_value(const NomadNode* node, NomadBytes* value_out);
  */
 bool nomad_node_has_value(const NomadNode* node);
 
+/** Structural classification of a trie node */
+typedef enum {
+    NOMAD_NODE_BRANCH = 0,     /* children, empty path fragment */
+    NOMAD_NODE_EXTENSION = 1,  /* children, non-empty path fragment */
+    NOMAD_NODE_LEAF = 2,       /* no children */
+} NomadNodeType;
+
+/**
+ * Classify a node as branch, extension or leaf
+ *
+ * @param node Node handle (NULL or empty handles classify as leaf)
+ */
+NomadNodeType nomad_node_type(const NomadNode* node);
+
+/**
+ * Get the bitmask of populated child branches
+ *
+ * Bit i set means the node has a child at branch nibble i.
+ *
+ * @param node Node handle (NULL or empty handles report no children)
+ */
+uint16_t nomad_node_child_mask(const NomadNode* node);
+
+/**
+ * Read the Merkle hash of one child without loading it
+ *
+ * The hash comes from the parent's cached child data, so the child node
+ * itself is never resolved from disk. Children whose encoding is shorter
+ * than 32 bytes are embedded in the parent per the Ethereum rules; their
+ * encoding is hashed so the output width is uniform.
+ *
+ * @param node Node handle
+ * @param branch Child branch nibble (0-15)
+ * @param[out] hash_out Buffer to receive the hash (must be at least 32 bytes)
+ * @return NOMAD_OK on success, NOMAD_ERR_NOT_FOUND if the branch is empty
+ */
+NomadError nomad_node_child_hash(const NomadNode* node, uint8_t branch, uint8_t* hash_out);
+
 /**
  * Get the Merkle hash of a node (32 bytes)
  * 
@@ -201,11 +288,60 @@ NomadError nomad_node_get_hash(const NomadNode* node, uint8_t* hash_out);
  */
 NomadNode* nomad_node_clone(const NomadNode* node);
 
+/**
+ * Create a new node sharing this node's path and version but holding a
+ * different value
+ *
+ * The original node is not modified; roots referencing it keep observing
+ * the old value.
+ *
+ * @param node Source node handle
+ * @param value New value bytes (may be NULL when value_len is 0)
+ * @param value_len New value length
+ * @param[out] node_out Pointer to receive the new node handle
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_node_with_value(
+    const NomadNode* node,
+    const uint8_t* value,
+    size_t value_len,
+    NomadNode** node_out
+);
+
+/**
+ * Create the canonical empty-trie root node
+ *
+ * The node has no path, value or children; its Merkle hash is the
+ * well-known Ethereum empty-trie hash
+ * 0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421.
+ *
+ * @param[out] node_out Pointer to receive the node handle
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_node_empty(NomadNode** node_out);
+
 /**
  * Free a node handle
  */
 void nomad_node_free(NomadNode* node);
 
+/* ============================================================
+ * Hashing
+ * ============================================================ */
+
+/**
+ * Compute the keccak-256 hash of an input buffer
+ *
+ * This is the hash used to derive 32-byte trie keys from
+ * application-level inputs (addresses, storage slots).
+ *
+ * @param input Input bytes (may be NULL when input_len is 0)
+ * @param input_len Input length in bytes
+ * @param[out] hash_out Buffer to receive the hash (must be at least 32 bytes)
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_keccak256(const uint8_t* input, size_t input_len, uint8_t* hash_out);
+
 /* ============================================================
  * Synchronous Write Operations
  * ============================================================ */
@@ -245,6 +381,297 @@ NomadError nomad_db_upsert(
     NomadNode** new_root_out
 );
 
+/**
+ * Apply updates only if the database is still at an expected version
+ *
+ * Atomically checks that the latest version equals expected_version and,
+ * if so, applies the updates targeting new_version. The check and the
+ * update are serialized against other nomad_db_upsert* calls, so two
+ * writers racing on the same expected_version cannot both succeed.
+ *
+ * @param db Database handle
+ * @param root Current root node (NULL for empty tree)
+ * @param updates Array of updates
+ * @param updates_len Number of updates
+ * @param expected_version Version the database must currently be at
+ * @param new_version Target version number for the update
+ * @param[out] new_root_out Pointer to receive the new root node
+ * @return NOMAD_OK on success, NOMAD_ERR_VERSION_CONFLICT if the latest
+ *         version no longer equals expected_version
+ */
+NomadError nomad_db_upsert_if_version(
+    NomadDb* db,
+    const NomadNode* root,
+    const NomadUpdate* updates,
+    size_t updates_len,
+    uint64_t expected_version,
+    uint64_t new_version,
+    NomadNode** new_root_out
+);
+
+/**
+ * Graft a subtree into the trie under a key prefix
+ *
+ * Inserts every key of subtree_root into the trie rooted at root, with
+ * prefix prepended to each key. The resulting root hash equals what
+ * upserting each prefixed key-value pair individually would produce.
+ *
+ * @param db Database handle
+ * @param root Current root node (NULL for empty tree)
+ * @param prefix Prefix bytes to graft under (may be empty)
+ * @param prefix_len Prefix length
+ * @param subtree_root Root of the subtree to graft
+ * @param version Target version number
+ * @param[out] new_root_out Pointer to receive the new root node
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_graft_subtree(
+    NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* prefix,
+    size_t prefix_len,
+    const NomadNode* subtree_root,
+    uint64_t version,
+    NomadNode** new_root_out
+);
+
+/**
+ * Extract the subtree under a key prefix as an independent trie
+ *
+ * The inverse of nomad_db_graft_subtree: returns a root containing only
+ * the keys under prefix, each with the prefix stripped.
+ *
+ * @param db Database handle
+ * @param root Root node to extract from
+ * @param prefix Prefix bytes selecting the subtree
+ * @param prefix_len Prefix length
+ * @param version Target version number
+ * @param[out] new_root_out Pointer to receive the extracted root (NULL when
+ *                          no keys exist under the prefix)
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_extract_subtree(
+    NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* prefix,
+    size_t prefix_len,
+    uint64_t version,
+    NomadNode** new_root_out
+);
+
+/* ============================================================
+ * Synchronous Traversal
+ * ============================================================ */
+
+/** A single key-value entry produced by traversal */
+typedef struct {
+    NomadBytes key;
+    NomadBytes value;
+    uint64_t version;       /* version the entry was last written at */
+} NomadKV;
+
+/** List of traversal results (free with nomad_kv_list_free) */
+typedef struct {
+    NomadKV* items;
+    size_t len;
+} NomadKVList;
+
+/** Free a NomadKVList and all contained entries */
+void nomad_kv_list_free(NomadKVList* list);
+
+/**
+ * Collect key-value pairs from the trie in ascending key order
+ *
+ * Visits all entries under the given prefix, optionally resuming strictly
+ * after a previous key, up to a maximum count. Pass start_after = NULL to
+ * begin at the first key, and limit = 0 for no limit. Large tries should
+ * be read in pages by passing the last returned key as start_after.
+ *
+ * @param db Database handle
+ * @param root Root node to traverse (NULL means empty trie)
+ * @param prefix Key prefix filter (NULL/0 for all keys)
+ * @param prefix_len Prefix length in bytes
+ * @param start_after Resume strictly after this key (NULL = from start)
+ * @param start_after_len Resume key length
+ * @param limit Maximum number of entries to collect (0 = unlimited)
+ * @param version Version number
+ * @param[out] list_out Receives the collected entries; caller must free
+ *                      with nomad_kv_list_free
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_traverse_sync(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* prefix,
+    size_t prefix_len,
+    const uint8_t* start_after,
+    size_t start_after_len,
+    size_t limit,
+    uint64_t version,
+    NomadKVList* list_out
+);
+
+/**
+ * Collect key-value pairs written at or after a minimum version
+ *
+ * Like nomad_db_traverse_sync, but skips every subtree whose root node
+ * version is below min_version. Because interior node versions bound the
+ * versions of the leaves beneath them, unchanged regions of the trie are
+ * pruned without being visited — incremental scans touch only the part
+ * of the trie that actually changed.
+ *
+ * @param db Database handle
+ * @param root Root node to traverse (NULL means empty trie)
+ * @param min_version Only collect entries written at this version or later
+ * @param start_after Resume strictly after this key (NULL = from start)
+ * @param start_after_len Resume key length
+ * @param limit Maximum number of entries to collect (0 = unlimited)
+ * @param version Version number
+ * @param[out] list_out Receives the collected entries; caller must free
+ *                      with nomad_kv_list_free
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_traverse_since(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t min_version,
+    const uint8_t* start_after,
+    size_t start_after_len,
+    size_t limit,
+    uint64_t version,
+    NomadKVList* list_out
+);
+
+/* ============================================================
+ * Key Navigation
+ * ============================================================ */
+
+/**
+ * Find the lexicographically smallest key in the trie
+ *
+ * Descends the leftmost branch only; does not scan the full trie.
+ *
+ * @param db Database handle
+ * @param root Root node to search from (NULL means empty trie)
+ * @param version Version number
+ * @param[out] key_out Receives the key (data == NULL for an empty trie);
+ *                     caller must free with nomad_bytes_free
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_first_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t version,
+    NomadBytes* key_out
+);
+
+/**
+ * Find the lexicographically largest key in the trie
+ *
+ * Descends the rightmost branch only; does not scan the full trie.
+ *
+ * @param db Database handle
+ * @param root Root node to search from (NULL means empty trie)
+ * @param version Version number
+ * @param[out] key_out Receives the key (data == NULL for an empty trie);
+ *                     caller must free with nomad_bytes_free
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_last_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t version,
+    NomadBytes* key_out
+);
+
+/**
+ * Find the smallest key strictly greater than the given key
+ *
+ * Prunes subtrees that cannot contain a larger key, so only the boundary
+ * path is visited.
+ *
+ * @param db Database handle
+ * @param root Root node to search from (NULL means empty trie)
+ * @param key Reference key bytes (need not exist in the trie)
+ * @param key_len Reference key length
+ * @param version Version number
+ * @param[out] key_out Receives the key (data == NULL if none exists);
+ *                     caller must free with nomad_bytes_free
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_next_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    NomadBytes* key_out
+);
+
+/**
+ * Find the largest key strictly less than the given key
+ *
+ * Prunes subtrees that cannot contain a smaller key, so only the boundary
+ * path and the rightmost edge of its left siblings are visited.
+ *
+ * @param db Database handle
+ * @param root Root node to search from (NULL means empty trie)
+ * @param key Reference key bytes (need not exist in the trie)
+ * @param key_len Reference key length
+ * @param version Version number
+ * @param[out] key_out Receives the key (data == NULL if none exists);
+ *                     caller must free with nomad_bytes_free
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_prev_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    NomadBytes* key_out
+);
+
+/** One node on the descent path from a root toward a key */
+typedef struct {
+    NomadNode* node;   /* Caller owns; free with nomad_node_free */
+    uint32_t depth;    /* 0 for the root */
+    uint8_t nibble;    /* Branch taken to reach this node; 0xFF for the root */
+} NomadPathStep;
+
+/**
+ * Collect every node on the path from root toward a key
+ *
+ * Descends the trie following the key's nibbles and returns a handle for
+ * each node encountered, in root-to-leaf order. The path may stop early
+ * when the key is absent; the steps still describe the deepest existing
+ * prefix. This is the raw material for Merkle proof construction.
+ *
+ * @param db Database handle
+ * @param root Root node to descend from
+ * @param key Target key bytes
+ * @param key_len Key length
+ * @param version Version to read
+ * @param[out] steps_out Malloc'd array of steps; free with nomad_path_steps_free
+ * @param[out] count_out Number of steps
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_walk_path(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    NomadPathStep** steps_out,
+    size_t* count_out
+);
+
+/**
+ * Free a path step array (the array only — node handles returned in the
+ * steps are owned by the caller and freed individually)
+ */
+void nomad_path_steps_free(NomadPathStep* steps);
+
 /* ============================================================
  * Version Management
  * ============================================================ */
@@ -261,6 +688,52 @@ uint64_t nomad_db_get_history_length(const NomadDb* db);
 /** Load the root node for a specific version */
 NomadError nomad_db_load_root(const NomadDb* db, uint64_t version, NomadNode** root_out);
 
+/**
+ * Load the root nodes for a batch of versions in one call
+ *
+ * Fills roots_out[i] with the root handle for versions[i], or NULL when
+ * that version has no root. On failure every handle loaded so far is
+ * freed and loaded_out reports how many versions succeeded before the
+ * error.
+ *
+ * @param db Database handle
+ * @param versions Versions to load
+ * @param count Number of versions
+ * @param[out] roots_out Array of at least count node handle slots
+ * @param[out] loaded_out Number of versions loaded (may be NULL)
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_load_root_batch(const NomadDb* db, const uint64_t* versions,
+                                    size_t count, NomadNode** roots_out,
+                                    size_t* loaded_out);
+
+/**
+ * Load the root node of the latest version in one call
+ *
+ * Combines nomad_db_get_latest_version and nomad_db_load_root without a
+ * window for a concurrent commit in between.
+ *
+ * @param db Database handle
+ * @param[out] version_out Latest version number (may be NULL)
+ * @param[out] root_out Root node handle, NULL if no root exists
+ * @return NOMAD_OK on success
+ */
+NomadError nomad_db_latest_root(const NomadDb* db, uint64_t* version_out, NomadNode** root_out);
+
+/**
+ * Read the root hash of a historical version
+ *
+ * Resolves the version's root internally and writes its Merkle hash
+ * without handing a node back to the caller, so no node handle has to
+ * be freed afterwards.
+ *
+ * @param db Database handle
+ * @param version Version to read
+ * @param[out] hash_out Buffer to receive the hash (must be at least 32 bytes)
+ * @return NOMAD_OK on success, NOMAD_ERR_NOT_FOUND if the version has no root
+ */
+NomadError nomad_db_root_hash(const NomadDb* db, uint64_t version, uint8_t* hash_out);
+
 /** Check if a version is valid */
 bool nomad_db_version_is_valid(const NomadDb* db, uint64_t version);
 
@@ -272,10 +745,15 @@ bool nomad_db_version_is_valid(const NomadDb* db, uint64_t version);
  * using the Rust bindings which have a complete async implementation.
  * 
  * Current status:
- * - nomad_fifo_create: Implemented (creates placeholder)
- * - nomad_fifo_start/stop: Stub (no-op)
- * - nomad_fifo_submit_*: Stub (no-op)
- * - nomad_fifo_poll*: Stub (always returns false)
+ * - nomad_fifo_create: Implemented
+ * - nomad_fifo_start/stop: Stub (no-op; no worker fibers yet)
+ * - nomad_fifo_submit_find / _high_priority: Implemented — the find runs
+ *   synchronously in the submitting thread (disk databases only) and the
+ *   completion is queued; high-priority completions are polled first
+ * - nomad_fifo_poll / nomad_fifo_poll_large_value: Implemented
+ * - nomad_fifo_submit_traverse: Stub (no-op)
+ * - nomad_fifo_poll_traverse / nomad_fifo_queue_depth: Implemented over
+ *   the completion queues
  * ============================================================ */
 
 /** Request type for async operations */
@@ -302,6 +780,7 @@ typedef struct {
     uint32_t value_len;
     uint8_t value[256];         /* Inline value for small results */
     uint8_t merkle_hash[32];
+    int priority;               /* Submission priority (0 = normal, 1 = high) */
 } NomadCompletion;
 
 /**
@@ -350,9 +829,33 @@ void nomad_fifo_submit_find(
     uint64_t user_data_hi
 );
 
+/**
+ * Submit a high-priority find request
+ *
+ * High-priority completions go to a separate queue that nomad_fifo_poll
+ * drains before the normal find queue, so they surface ahead of any
+ * backlog of normal completions. Completions carry the priority field
+ * set to 1.
+ *
+ * @param fifo FIFO handle
+ * @param key Key bytes
+ * @param key_len Key length
+ * @param version Version to query
+ * @param user_data_lo User data (low 64 bits), returned in completion
+ * @param user_data_hi User data (high 64 bits), returned in completion
+ */
+void nomad_fifo_submit_find_high_priority(
+    NomadFifo* fifo,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    uint64_t user_data_lo,
+    uint64_t user_data_hi
+);
+
 /**
  * Submit a traverse request (non-blocking)
- * 
+ *
  * @param fifo FIFO handle
  * @param prefix Key prefix bytes
  * @param prefix_len Prefix length
@@ -405,6 +908,16 @@ bool nomad_fifo_poll_large_value(
     NomadBytes* data_out
 );
 
+/**
+ * Number of submitted requests not yet surfaced as completions
+ *
+ * Counts both the find and traverse queues.
+ *
+ * @param fifo FIFO handle
+ * @return Pending request count, 0 if fifo is NULL
+ */
+size_t nomad_fifo_queue_depth(const NomadFifo* fifo);
+
 #ifdef __cplusplus
 }
 #endif
@@ -0,0 +1,51 @@
+package monaddb
+
+import "time"
+
+// Metrics receives operation measurements from the library. Implementations
+// bridge to whatever metrics system the application uses; all methods may be
+// called concurrently.
+type Metrics interface {
+	// ObserveOp is called once per timed operation with its duration.
+	ObserveOp(op string, duration time.Duration)
+}
+
+// Tracer creates spans around timed operations. StartSpan returns the
+// function that ends the span; both sides may be called concurrently.
+type Tracer interface {
+	StartSpan(op string) (end func())
+}
+
+// Option configures a database at construction time, applied by
+// OpenDiskWithOptions after the underlying stores are open.
+type Option func(db *DB)
+
+// WithLogger installs a diagnostic logger at construction, so events from
+// the open itself onward are captured. Equivalent to calling SetLogger
+// immediately after open.
+func WithLogger(l Logger) Option {
+	return func(db *DB) { db.SetLogger(l) }
+}
+
+// WithMetrics installs a metrics sink that observes every timed operation
+// (Find, Put, Upsert, ...) with its duration.
+func WithMetrics(m Metrics) Option {
+	return func(db *DB) {
+		if m == nil {
+			db.metrics.Store(nil)
+			return
+		}
+		db.metrics.Store(&m)
+	}
+}
+
+// WithTracer installs a tracer that opens a span for every timed operation.
+func WithTracer(t Tracer) Option {
+	return func(db *DB) {
+		if t == nil {
+			db.tracer.Store(nil)
+			return
+		}
+		db.tracer.Store(&t)
+	}
+}
@@ -0,0 +1,58 @@
+package monaddb
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingMetrics struct {
+	ops atomic.Uint64
+}
+
+func (m *countingMetrics) ObserveOp(op string, d time.Duration) { m.ops.Add(1) }
+
+type countingTracer struct {
+	spans atomic.Uint64
+	ends  atomic.Uint64
+}
+
+func (t *countingTracer) StartSpan(op string) func() {
+	t.spans.Add(1)
+	return func() { t.ends.Add(1) }
+}
+
+// TestConstructionOptions tests the WithLogger/WithMetrics/WithTracer path.
+func TestConstructionOptions(t *testing.T) {
+	rec := &recordingLogger{}
+	metrics := &countingMetrics{}
+	tracer := &countingTracer{}
+
+	db, err := OpenDiskWithOptions(t.TempDir()+"/opts.db", Options{Create: true, HistoryLength: 10},
+		WithLogger(rec), WithMetrics(metrics), WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := db.FindFromRoot(root, makeKey32(0x01), 1); err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+
+	if metrics.ops.Load() == 0 {
+		t.Error("Metrics sink should observe timed operations")
+	}
+	if tracer.spans.Load() == 0 || tracer.spans.Load() != tracer.ends.Load() {
+		t.Errorf("Every span should start and end, got %d/%d", tracer.spans.Load(), tracer.ends.Load())
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.msgs) == 0 || rec.msgs[0] != "database opened" {
+		t.Errorf("Open should be logged first, got %v", rec.msgs)
+	}
+}
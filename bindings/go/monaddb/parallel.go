@@ -0,0 +1,82 @@
+package monaddb
+
+import (
+	"context"
+	"sync"
+)
+
+// TraverseParallel walks every key-value pair reachable from root using
+// multiple goroutines. The trie is partitioned by the first key byte and
+// the 256 partitions are spread over the workers, so entries arrive in
+// non-deterministic order and fn must be safe to call concurrently. An
+// error from any worker — or context cancellation — stops the remaining
+// workers and is returned; when several workers fail, one of their errors
+// is returned.
+func (db *DB) TraverseParallel(root *Node, version uint64, parallelism int, ctx context.Context, fn func(key, value []byte) error) error {
+	if db.ptr == nil {
+		return ErrNullPointer
+	}
+	if parallelism <= 0 || fn == nil {
+		return ErrInvalidArgument
+	}
+	if root == nil || root.ptr == nil {
+		return nil // Empty trie
+	}
+	if parallelism > 256 {
+		parallelism = 256
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Workers pull one-byte prefixes from a shared channel, so a skewed
+	// key distribution does not idle the other goroutines.
+	prefixes := make(chan byte, 256)
+	for b := 0; b < 256; b++ {
+		prefixes <- byte(b)
+	}
+	close(prefixes)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range prefixes {
+				if ctx.Err() != nil {
+					return
+				}
+				err := db.forEach(root, []byte{prefix}, version, func(kv kvEntry) (bool, error) {
+					if err := ctx.Err(); err != nil {
+						return false, err
+					}
+					if err := fn(kv.Key, kv.Value); err != nil {
+						return false, err
+					}
+					return true, nil
+				})
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,89 @@
+package monaddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestTraverseParallel tests that a parallel walk sees every entry once.
+func TestTraverseParallel(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var updates []Update
+	for i := 0; i < 64; i++ {
+		updates = append(updates, Update{
+			Key:   makeKey32(byte(i)),
+			Value: []byte(fmt.Sprintf("value-%d", i)),
+		})
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[string]string)
+	err = db.TraverseParallel(root, 1, 4, context.Background(), func(key, value []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[string(key)] = string(value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TraverseParallel failed: %v", err)
+	}
+	if len(seen) != len(updates) {
+		t.Fatalf("Expected %d entries, got %d", len(updates), len(seen))
+	}
+	for i, u := range updates {
+		if seen[string(u.Key)] != string(u.Value) {
+			t.Errorf("Entry %d has wrong value %q", i, seen[string(u.Key)])
+		}
+	}
+
+	if err := db.TraverseParallel(root, 1, 0, context.Background(), nil); err != ErrInvalidArgument {
+		t.Errorf("Invalid arguments should be rejected, got %v", err)
+	}
+}
+
+// TestTraverseParallelError tests that a worker error stops the walk.
+func TestTraverseParallelError(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var updates []Update
+	for i := 0; i < 16; i++ {
+		updates = append(updates, Update{Key: makeKey32(byte(i)), Value: []byte("v")})
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	boom := errors.New("boom")
+	err = db.TraverseParallel(root, 1, 4, context.Background(), func(key, value []byte) error {
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("Worker error should propagate, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = db.TraverseParallel(root, 1, 4, ctx, func(key, value []byte) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Cancellation should surface, got %v", err)
+	}
+}
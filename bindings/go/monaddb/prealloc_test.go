@@ -0,0 +1,39 @@
+package monaddb
+
+import "testing"
+
+// TestSetPreallocSize tests reservation setup and the memory-mode rejection.
+func TestSetPreallocSize(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/prealloc.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetPreallocSize(1 << 20); err != nil {
+		t.Fatalf("SetPreallocSize failed: %v", err)
+	}
+
+	// Writes proceed normally into reserved space
+	var root *Node
+	for i := byte(1); i <= 10; i++ {
+		root, err = db.Put(root, makeKey32(i), []byte{i}, uint64(i))
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	// Zero disables
+	if err := db.SetPreallocSize(0); err != nil {
+		t.Fatalf("Disabling preallocation failed: %v", err)
+	}
+
+	mem, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer mem.Close()
+	if err := mem.SetPreallocSize(1 << 20); err != ErrNotSupported {
+		t.Errorf("Memory databases should reject preallocation, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package monaddb
+
+import (
+	"encoding/hex"
+	"sort"
+)
+
+// Proof is the Merkle path for one key: the hashes of every node on the
+// descent from the root to the key's leaf, root first, together with the
+// value proven. Nil Value proves absence — the path then ends at the
+// deepest node the key's nibbles reach.
+type Proof struct {
+	Key    []byte
+	Value  []byte
+	Hashes [][32]byte
+}
+
+// ProveBatch builds proofs for a transaction's declared access list in
+// one batch over the trie. Duplicate keys are collapsed and the batch is
+// walked in sorted key order, so keys sharing a path prefix revisit the
+// same nodes back to back while they are hot instead of scattering the
+// descents. Each proof lands in the result under hex.EncodeToString of
+// its key; absent keys get an absence proof with a nil Value.
+func (db *DB) ProveBatch(root *Node, accessList [][]byte, version uint64) (map[string]*Proof, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	keys := make([]string, 0, len(accessList))
+	seen := make(map[string]bool, len(accessList))
+	for _, key := range accessList {
+		k := hex.EncodeToString(key)
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	proofs := make(map[string]*Proof, len(keys))
+	for _, k := range keys {
+		key, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, ErrInvalidArgument
+		}
+
+		proof := &Proof{Key: key}
+		err = db.WalkPath(root, key, version, func(node *Node, depth int, nibble byte) error {
+			hash, err := node.Hash()
+			if err != nil {
+				return err
+			}
+			proof.Hashes = append(proof.Hashes, hash)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		node, err := db.FindFromRoot(root, key, version)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			if proof.Value, err = node.Value(); err != nil {
+				return nil, err
+			}
+		}
+		// Nil node: absence proof — the walked path shows where the key would be
+		proofs[k] = proof
+	}
+	return proofs, nil
+}
+
+// CompactProofBundle is a batch of proofs with the shared nodes factored
+// out: every distinct path hash is stored once and the per-key paths
+// reference it by index. Proofs in one block overlap heavily near the
+// root, so the bundle is a fraction of the individual proofs' size.
+type CompactProofBundle struct {
+	Hashes [][32]byte          // deduplicated proof nodes
+	Paths  map[string][]uint32 // per hex key: indices into Hashes, root first
+	Values map[string][]byte   // per hex key; keys proven absent are missing
+}
+
+// CompactProofs factorizes a proof set into a deduplicated bundle. The
+// node list is ordered by first use over the sorted keys, so compacting
+// the same proofs always yields the same bundle.
+func CompactProofs(proofs map[string]*Proof) (*CompactProofBundle, error) {
+	if proofs == nil {
+		return nil, ErrNullPointer
+	}
+
+	keys := make([]string, 0, len(proofs))
+	for k := range proofs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bundle := &CompactProofBundle{
+		Paths:  make(map[string][]uint32, len(proofs)),
+		Values: make(map[string][]byte),
+	}
+	index := make(map[[32]byte]uint32)
+	for _, k := range keys {
+		proof := proofs[k]
+		if proof == nil {
+			return nil, ErrInvalidArgument
+		}
+		path := make([]uint32, len(proof.Hashes))
+		for i, hash := range proof.Hashes {
+			at, ok := index[hash]
+			if !ok {
+				at = uint32(len(bundle.Hashes))
+				index[hash] = at
+				bundle.Hashes = append(bundle.Hashes, hash)
+			}
+			path[i] = at
+		}
+		bundle.Paths[k] = path
+		if proof.Value != nil {
+			bundle.Values[k] = proof.Value
+		}
+	}
+	return bundle, nil
+}
+
+// ExpandProofBundle reconstructs the proof for one key from a bundle.
+// Keys the bundle does not cover return ErrNotFound; an index pointing
+// outside the node list marks a corrupt bundle and returns
+// ErrInvalidArgument.
+func ExpandProofBundle(bundle *CompactProofBundle, key []byte) (*Proof, error) {
+	if bundle == nil {
+		return nil, ErrNullPointer
+	}
+
+	k := hex.EncodeToString(key)
+	path, ok := bundle.Paths[k]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	proof := &Proof{
+		Key:    append([]byte(nil), key...),
+		Hashes: make([][32]byte, len(path)),
+	}
+	for i, at := range path {
+		if at >= uint32(len(bundle.Hashes)) {
+			return nil, ErrInvalidArgument
+		}
+		proof.Hashes[i] = bundle.Hashes[at]
+	}
+	if value, ok := bundle.Values[k]; ok {
+		proof.Value = value
+	}
+	return proof, nil
+}
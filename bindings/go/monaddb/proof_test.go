@@ -0,0 +1,124 @@
+package monaddb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestProveBatch tests proofs for an access list.
+func TestProveBatch(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Upsert(nil, []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x12), Value: []byte("two")},
+		{Key: makeKey32(0x21), Value: []byte("three")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	accessList := [][]byte{
+		makeKey32(0x11),
+		makeKey32(0x21),
+		makeKey32(0x11), // duplicates collapse
+		makeKey32(0x99), // absent: absence proof
+	}
+	proofs, err := db.ProveBatch(root, accessList, 1)
+	if err != nil {
+		t.Fatalf("ProveBatch failed: %v", err)
+	}
+	if len(proofs) != 3 {
+		t.Fatalf("Expected 3 proofs, got %d", len(proofs))
+	}
+
+	rootHash, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	p := proofs[hex.EncodeToString(makeKey32(0x11))]
+	if p == nil {
+		t.Fatal("Proof for a present key should exist")
+	}
+	if !bytes.Equal(p.Value, []byte("one")) {
+		t.Errorf("Proof should carry the value, got %q", p.Value)
+	}
+	if len(p.Hashes) == 0 || p.Hashes[0] != rootHash {
+		t.Error("Proof paths should start at the root hash")
+	}
+
+	absent := proofs[hex.EncodeToString(makeKey32(0x99))]
+	if absent == nil || absent.Value != nil {
+		t.Errorf("An absent key should get an absence proof, got %+v", absent)
+	}
+	if len(absent.Hashes) == 0 {
+		t.Error("An absence proof still walks the existing prefix")
+	}
+}
+
+// TestCompactProofs tests deduplication and round-trip expansion.
+func TestCompactProofs(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Upsert(nil, []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x12), Value: []byte("two")},
+		{Key: makeKey32(0x13), Value: []byte("three")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	accessList := [][]byte{makeKey32(0x11), makeKey32(0x12), makeKey32(0x13)}
+	proofs, err := db.ProveBatch(root, accessList, 1)
+	if err != nil {
+		t.Fatalf("ProveBatch failed: %v", err)
+	}
+
+	bundle, err := CompactProofs(proofs)
+	if err != nil {
+		t.Fatalf("CompactProofs failed: %v", err)
+	}
+
+	// The shared prefix nodes must be stored once, not once per proof
+	total := 0
+	for _, p := range proofs {
+		total += len(p.Hashes)
+	}
+	if len(bundle.Hashes) >= total {
+		t.Errorf("Shared nodes should deduplicate: %d in bundle, %d across proofs",
+			len(bundle.Hashes), total)
+	}
+
+	for _, key := range accessList {
+		expanded, err := ExpandProofBundle(bundle, key)
+		if err != nil {
+			t.Fatalf("ExpandProofBundle failed: %v", err)
+		}
+		original := proofs[hex.EncodeToString(key)]
+		if len(expanded.Hashes) != len(original.Hashes) {
+			t.Fatalf("Expanded path length mismatch for %x", key)
+		}
+		for i := range expanded.Hashes {
+			if expanded.Hashes[i] != original.Hashes[i] {
+				t.Errorf("Expanded hash %d mismatches for %x", i, key)
+			}
+		}
+		if !bytes.Equal(expanded.Value, original.Value) {
+			t.Errorf("Expanded value mismatches for %x", key)
+		}
+	}
+
+	if _, err := ExpandProofBundle(bundle, makeKey32(0x99)); err != ErrNotFound {
+		t.Errorf("Uncovered keys should return ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package monaddb
+
+import "sync"
+
+// pruneTracker watches the earliest retained version and reports versions
+// that fall out of the history window.
+type pruneTracker struct {
+	mu           sync.Mutex
+	fn           func(version uint64)
+	lastEarliest uint64
+	seeded       bool
+}
+
+// SetPruneCallback registers fn to be called once for every version that
+// leaves the retention window. The engine prunes automatically as writes
+// advance the history window, so the callback fires synchronously at the
+// start of the Upsert that is about to push the version out — before its
+// data is freed — giving the application a last chance to react (expire a
+// cache, surface ErrVersionOutOfRange to in-flight queries, and so on).
+//
+// Versions pinned with LockVersion are reported like any other: the
+// callback is a notification, not a veto. Passing nil removes the callback.
+func (db *DB) SetPruneCallback(fn func(version uint64)) {
+	db.pruned.mu.Lock()
+	db.pruned.fn = fn
+	db.pruned.seeded = false
+	db.pruned.mu.Unlock()
+}
+
+// notifyPrunes fires the prune callback for every version the write at
+// newVersion will push out of the history window. Called before the C-layer
+// upsert, so the data for the reported versions is still present.
+func (db *DB) notifyPrunes(newVersion uint64) {
+	db.pruned.mu.Lock()
+	defer db.pruned.mu.Unlock()
+	if db.pruned.fn == nil {
+		return
+	}
+
+	history := db.HistoryLength()
+	if history == 0 || newVersion < history {
+		return
+	}
+	newEarliest := newVersion - history + 1
+
+	if !db.pruned.seeded {
+		db.pruned.lastEarliest = db.EarliestVersion()
+		db.pruned.seeded = true
+	}
+	for v := db.pruned.lastEarliest; v < newEarliest; v++ {
+		db.log().Debug("version leaving history window", "version", v)
+		db.pruned.fn(v)
+	}
+	if newEarliest > db.pruned.lastEarliest {
+		db.pruned.lastEarliest = newEarliest
+	}
+}
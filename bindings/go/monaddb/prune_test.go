@@ -0,0 +1,55 @@
+package monaddb
+
+import "testing"
+
+// TestPruneCallback tests that versions leaving the history window are
+// reported, and that the data is still readable inside the callback.
+func TestPruneCallback(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/prune.db", true, 3)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 3; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	var pruned []uint64
+	db.SetPruneCallback(func(version uint64) {
+		pruned = append(pruned, version)
+		// The callback fires before the data is freed: the version's
+		// root must still be loadable here.
+		if !db.VersionIsValid(version) {
+			t.Errorf("Version %d should still be valid inside the callback", version)
+		}
+		if _, err := db.LoadRoot(version); err != nil {
+			t.Errorf("Version %d should still be readable inside the callback: %v", version, err)
+		}
+	})
+
+	// Writing versions 4 and 5 pushes 1 and 2 out of the 3-version window
+	for v := uint64(4); v <= 5; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	if len(pruned) != 2 || pruned[0] != 1 || pruned[1] != 2 {
+		t.Errorf("Expected versions 1 and 2 to be reported, got %v", pruned)
+	}
+
+	// Removing the callback stops notifications
+	db.SetPruneCallback(nil)
+	if _, err := db.Put(root, makeKey32(6), []byte{6}, 6); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if len(pruned) != 2 {
+		t.Errorf("No callbacks should fire after removal, got %v", pruned)
+	}
+}
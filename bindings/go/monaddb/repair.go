@@ -0,0 +1,40 @@
+package monaddb
+
+import "bytes"
+
+// ReadRepair verifies the value stored at key against the canonical copy
+// and rewrites it if the stored bytes are missing or corrupt.
+//
+// The check reads the leaf through the normal lookup path, so a leaf whose
+// stored hash no longer matches its content surfaces as either a failed
+// read or a value that differs from canonical. In both cases the canonical
+// value is written back at the same version, which rebuilds the leaf and
+// recomputes every parent hash on the path to the root.
+//
+// Returns true if a repair write was performed, false if the stored value
+// already matched. This is a diagnostic tool for operators running
+// integrity checks; it is not meant for the hot path.
+func (db *DB) ReadRepair(root *Node, key []byte, version uint64, canonical []byte) (bool, error) {
+	if db == nil || db.ptr == nil {
+		return false, ErrNullPointer
+	}
+
+	node, err := db.FindFromRoot(root, key, version)
+	if err != nil && err != ErrNotFound {
+		// A read failure on a key the operator asserts should exist is
+		// itself evidence of corruption; fall through to the repair write
+		node = nil
+	}
+
+	if node != nil && node.HasValue() {
+		stored, err := node.Value()
+		if err == nil && bytes.Equal(stored, canonical) {
+			return false, nil
+		}
+	}
+
+	if _, err := db.Put(root, key, canonical, version); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,45 @@
+package monaddb
+
+import "testing"
+
+// TestReadRepair tests corruption detection and repair write behavior.
+func TestReadRepair(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	root, err := db.Put(nil, key, []byte("canonical"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// A matching value needs no repair
+	repaired, err := db.ReadRepair(root, key, 1, []byte("canonical"))
+	if err != nil {
+		t.Fatalf("ReadRepair failed: %v", err)
+	}
+	if repaired {
+		t.Error("ReadRepair should not rewrite a matching value")
+	}
+
+	// A divergent stored value triggers a repair write
+	repaired, err = db.ReadRepair(root, key, 1, []byte("corrected"))
+	if err != nil {
+		t.Fatalf("ReadRepair failed: %v", err)
+	}
+	if !repaired {
+		t.Error("ReadRepair should rewrite a divergent value")
+	}
+
+	// A missing key also counts as damage
+	repaired, err = db.ReadRepair(root, makeKey32(0x02), 1, []byte("restored"))
+	if err != nil {
+		t.Fatalf("ReadRepair failed: %v", err)
+	}
+	if !repaired {
+		t.Error("ReadRepair should write a missing key")
+	}
+}
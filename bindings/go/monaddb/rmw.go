@@ -0,0 +1,111 @@
+package monaddb
+
+// UpdateValue applies a read-modify-write to a single key in one call:
+// fn receives the current value (nil when the key is absent) and returns
+// the new one (nil to delete). Writes are single-threaded by contract, so
+// the read and the write cannot be interleaved by another writer and the
+// whole operation is atomic. Returning nil for an absent key is a no-op
+// and hands the root back unchanged.
+func (db *DB) UpdateValue(root *Node, key []byte, version uint64, fn func(old []byte) []byte) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if fn == nil {
+		return nil, ErrInvalidArgument
+	}
+
+	// A nil root (empty trie) or nil node (absent key) hands fn a nil value
+	var old []byte
+	var node *Node
+	if root != nil {
+		var err error
+		if node, err = db.FindFromRoot(root, key, version); err != nil {
+			return nil, err
+		}
+	}
+	if node != nil {
+		var err error
+		if old, err = node.Value(); err != nil {
+			return nil, err
+		}
+	}
+
+	value := fn(old)
+	if value == nil {
+		if old == nil && node == nil {
+			return root, nil // Nothing to delete
+		}
+		return db.Delete(root, key, version)
+	}
+	return db.Put(root, key, value, version)
+}
+
+// AppendToValue appends data to the value stored under key, creating the
+// key when absent. The combined size is checked against
+// Options.MaxAppendedValueSize before anything is written; over the cap
+// the root is left untouched and ErrValueTooLarge comes back. Appending
+// nothing is a no-op.
+func (db *DB) AppendToValue(root *Node, key []byte, appendData []byte, version uint64) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if len(appendData) == 0 {
+		return root, nil
+	}
+
+	// A nil root or nil node means the key is absent: appending starts a
+	// fresh value
+	var old []byte
+	var node *Node
+	if root != nil {
+		var err error
+		if node, err = db.FindFromRoot(root, key, version); err != nil {
+			return nil, err
+		}
+	}
+	if node != nil {
+		var err error
+		if old, err = node.Value(); err != nil {
+			return nil, err
+		}
+	}
+
+	if db.maxAppendedValueSize > 0 && len(old)+len(appendData) > db.maxAppendedValueSize {
+		return nil, ErrValueTooLarge
+	}
+
+	combined := make([]byte, 0, len(old)+len(appendData))
+	combined = append(combined, old...)
+	combined = append(combined, appendData...)
+	return db.Put(root, key, combined, version)
+}
+
+// TruncateValue trims the value stored under key to at most maxLen bytes
+// and writes the shortened value back — the enforcement half of a storage
+// quota, with AppendToValue as its growing counterpart. A value already
+// within the limit hands the root back unchanged without writing; a
+// missing key returns ErrNotFound.
+func (db *DB) TruncateValue(root *Node, key []byte, maxLen int, version uint64) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if maxLen < 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	node, err := db.FindFromRoot(root, key, version)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, ErrNotFound
+	}
+	value, err := node.Value()
+	if err != nil {
+		return nil, err
+	}
+	if len(value) <= maxLen {
+		return root, nil // Already within the quota
+	}
+	return db.Put(root, key, value[:maxLen], version)
+}
@@ -0,0 +1,155 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestUpdateValue tests the read-modify-write paths.
+func TestUpdateValue(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	// An absent key arrives as nil
+	root, err := db.UpdateValue(nil, makeKey32(0x01), 1, func(old []byte) []byte {
+		if old != nil {
+			t.Errorf("Absent key should read as nil, got %q", old)
+		}
+		return []byte("a")
+	})
+	if err != nil {
+		t.Fatalf("UpdateValue failed: %v", err)
+	}
+
+	// An existing value is transformed in place
+	root, err = db.UpdateValue(root, makeKey32(0x01), 2, func(old []byte) []byte {
+		return append(old, 'b')
+	})
+	if err != nil {
+		t.Fatalf("UpdateValue failed: %v", err)
+	}
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 2)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "ab" {
+		t.Errorf("Expected transformed value \"ab\", got %q, %v", value, err)
+	}
+
+	// Returning nil deletes
+	root, err = db.UpdateValue(root, makeKey32(0x01), 3, func(old []byte) []byte {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateValue failed: %v", err)
+	}
+	if _, err := db.FindFromRoot(root, makeKey32(0x01), 3); err != ErrNotFound {
+		t.Errorf("Key should be deleted, got %v", err)
+	}
+
+	// nil for an absent key is a no-op
+	same, err := db.UpdateValue(root, makeKey32(0x02), 4, func(old []byte) []byte {
+		return nil
+	})
+	if err != nil || same != root {
+		t.Errorf("Deleting an absent key should hand the root back, got %v, %v", same, err)
+	}
+
+	if _, err := db.UpdateValue(root, makeKey32(0x01), 5, nil); err != ErrInvalidArgument {
+		t.Errorf("A nil transform should be rejected, got %v", err)
+	}
+}
+
+// TestAppendToValue tests appending with and without the size cap.
+func TestAppendToValue(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	// Appending to an absent key starts the value
+	root, err := db.AppendToValue(nil, makeKey32(0x01), []byte("hello"), 1)
+	if err != nil {
+		t.Fatalf("AppendToValue failed: %v", err)
+	}
+	root, err = db.AppendToValue(root, makeKey32(0x01), []byte(" world"), 2)
+	if err != nil {
+		t.Fatalf("AppendToValue failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 2)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "hello world" {
+		t.Errorf("Expected combined value, got %q, %v", value, err)
+	}
+
+	if same, err := db.AppendToValue(root, makeKey32(0x01), nil, 3); err != nil || same != root {
+		t.Errorf("Appending nothing should be a no-op, got %v, %v", same, err)
+	}
+}
+
+// TestAppendToValueCap tests the MaxAppendedValueSize limit.
+func TestAppendToValueCap(t *testing.T) {
+	db, err := OpenDiskWithOptions(t.TempDir()+"/append.db", Options{
+		Create:               true,
+		MaxAppendedValueSize: 8,
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.AppendToValue(nil, makeKey32(0x01), []byte("12345"), 1)
+	if err != nil {
+		t.Fatalf("AppendToValue failed: %v", err)
+	}
+	if _, err := db.AppendToValue(root, makeKey32(0x01), []byte("6789"), 2); err != ErrValueTooLarge {
+		t.Errorf("Exceeding the cap should fail, got %v", err)
+	}
+	if _, err := db.AppendToValue(root, makeKey32(0x01), []byte("678"), 2); err != nil {
+		t.Errorf("Appending within the cap should work, got %v", err)
+	}
+}
+
+// TestTruncateValue tests trimming a value to a quota.
+func TestTruncateValue(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("hello world"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	newRoot, err := db.TruncateValue(root, makeKey32(0x01), 5, 2)
+	if err != nil {
+		t.Fatalf("TruncateValue failed: %v", err)
+	}
+	node, err := db.FindFromRoot(newRoot, makeKey32(0x01), 2)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "hello" {
+		t.Errorf("Expected trimmed value, got %q, %v", value, err)
+	}
+
+	// A value within the limit is not rewritten
+	if same, err := db.TruncateValue(newRoot, makeKey32(0x01), 10, 3); err != nil || same != newRoot {
+		t.Errorf("A short enough value should be a no-op, got %v, %v", same, err)
+	}
+
+	if _, err := db.TruncateValue(newRoot, makeKey32(0x02), 5, 3); err != ErrNotFound {
+		t.Errorf("A missing key should return ErrNotFound, got %v", err)
+	}
+	if _, err := db.TruncateValue(newRoot, makeKey32(0x01), -1, 3); err != ErrInvalidArgument {
+		t.Errorf("A negative limit should be rejected, got %v", err)
+	}
+}
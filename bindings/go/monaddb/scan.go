@@ -0,0 +1,116 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import (
+	"context"
+	"unsafe"
+)
+
+// KeyVersionPair is one entry of an incremental scan: a key, its current
+// value, and the version that last wrote it.
+type KeyVersionPair struct {
+	Key     []byte
+	Value   []byte
+	Version uint64
+}
+
+// ScanSince streams every entry created or modified at a version strictly
+// greater than sinceVersion, in ascending key order. The scan reads the
+// latest trie and prunes on interior node versions, so unchanged regions
+// are skipped without being visited — the cost scales with the amount of
+// change, not the size of the state. Cancelling the context stops the
+// stream; the channel is closed when the scan ends for any reason.
+// ScanSince(0, ctx) streams the whole state.
+func (db *DB) ScanSince(sinceVersion uint64, ctx context.Context) (<-chan KeyVersionPair, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	version := db.LatestVersion()
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan KeyVersionPair)
+	go func() {
+		defer close(out)
+		db.forEachSince(root, sinceVersion+1, version, func(kv kvEntry) (bool, error) {
+			select {
+			case out <- KeyVersionPair{Key: kv.Key, Value: kv.Value, Version: kv.Version}:
+				return true, nil
+			case <-ctx.Done():
+				return false, nil
+			}
+		})
+	}()
+	return out, nil
+}
+
+// forEachSince pages through the entries written at minVersion or later,
+// mirroring forEach but pruning subtrees older than minVersion.
+func (db *DB) forEachSince(root *Node, minVersion, version uint64, fn func(kv kvEntry) (bool, error)) error {
+	if root == nil || root.ptr == nil {
+		return nil // Empty trie
+	}
+
+	var startAfter []byte
+	for {
+		var afterPtr *C.uint8_t
+		if startAfter != nil {
+			afterPtr = (*C.uint8_t)(unsafe.Pointer(&startAfter[0]))
+		}
+
+		var list C.NomadKVList
+		code := C.nomad_db_traverse_since(
+			db.ptr,
+			root.ptr,
+			C.uint64_t(minVersion),
+			afterPtr,
+			C.size_t(len(startAfter)),
+			C.size_t(traversePageSize),
+			C.uint64_t(version),
+			&list,
+		)
+		if err := codeToError(int(code)); err != nil {
+			return err
+		}
+
+		n := int(list.len)
+		if n > 0 {
+			items := unsafe.Slice(list.items, n)
+			for i := range items {
+				kv := kvEntry{
+					Version: uint64(items[i].version),
+				}
+				if items[i].key.data != nil && items[i].key.len > 0 {
+					kv.Key = C.GoBytes(unsafe.Pointer(items[i].key.data), C.int(items[i].key.len))
+				}
+				if items[i].value.data != nil && items[i].value.len > 0 {
+					raw := C.GoBytes(unsafe.Pointer(items[i].value.data), C.int(items[i].value.len))
+					value, err := db.decodeValue(raw)
+					if err != nil {
+						C.nomad_kv_list_free(&list)
+						return err
+					}
+					kv.Value = value
+				}
+
+				cont, err := fn(kv)
+				if err != nil || !cont {
+					C.nomad_kv_list_free(&list)
+					return err
+				}
+				startAfter = kv.Key
+			}
+		}
+		C.nomad_kv_list_free(&list)
+
+		if n < traversePageSize {
+			return nil // Final page
+		}
+	}
+}
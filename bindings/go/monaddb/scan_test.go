@@ -0,0 +1,75 @@
+package monaddb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// TestScanSince tests incremental scans against the version they follow.
+func TestScanSince(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/scan.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Upsert(nil, []Update{
+		{Key: makeKey32(0x01), Value: []byte("one")},
+		{Key: makeKey32(0x02), Value: []byte("two")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert at version 1 failed: %v", err)
+	}
+	_, err = db.Upsert(root, []Update{
+		{Key: makeKey32(0x01), Value: []byte("one-v2")},
+		{Key: makeKey32(0x03), Value: []byte("three")},
+	}, 2)
+	if err != nil {
+		t.Fatalf("Upsert at version 2 failed: %v", err)
+	}
+
+	stream, err := db.ScanSince(1, context.Background())
+	if err != nil {
+		t.Fatalf("ScanSince failed: %v", err)
+	}
+	changed := make(map[string]KeyVersionPair)
+	for pair := range stream {
+		changed[string(pair.Key)] = pair
+	}
+	if len(changed) != 2 {
+		t.Fatalf("Expected 2 changed entries, got %d", len(changed))
+	}
+	if p, ok := changed[string(makeKey32(0x01))]; !ok || p.Version != 2 || !bytes.Equal(p.Value, []byte("one-v2")) {
+		t.Errorf("Modified key should stream with its new value, got %+v", p)
+	}
+	if _, ok := changed[string(makeKey32(0x03))]; !ok {
+		t.Error("Created key should stream")
+	}
+	if _, ok := changed[string(makeKey32(0x02))]; ok {
+		t.Error("Unchanged key should not stream")
+	}
+
+	// Since version 0 the stream covers the whole state
+	stream, err = db.ScanSince(0, context.Background())
+	if err != nil {
+		t.Fatalf("ScanSince failed: %v", err)
+	}
+	count := 0
+	for range stream {
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Full scan should stream 3 entries, got %d", count)
+	}
+
+	// A cancelled context closes the stream without draining it
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream, err = db.ScanSince(0, ctx)
+	if err != nil {
+		t.Fatalf("ScanSince failed: %v", err)
+	}
+	for range stream {
+	}
+}
@@ -0,0 +1,170 @@
+package monaddb
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// sentinelStore holds the per-database HMAC secret and the recorded
+// sentinel for each version. For disk databases it persists to a sidecar
+// file next to the database so sentinels survive restarts.
+type sentinelStore struct {
+	mu     sync.Mutex
+	loaded bool
+	key    []byte
+	macs   map[uint64][]byte
+}
+
+// sentinelFile is the JSON layout of the sidecar file.
+type sentinelFile struct {
+	Key       string            `json:"key"`
+	Sentinels map[string]string `json:"sentinels"`
+}
+
+func (s *sentinelStore) load(path string) error {
+	if s.loaded {
+		return nil
+	}
+	s.loaded = true
+	s.macs = make(map[uint64][]byte)
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var f sentinelFile
+			if err := json.Unmarshal(data, &f); err != nil {
+				return err
+			}
+			if s.key, err = hex.DecodeString(f.Key); err != nil {
+				return err
+			}
+			for v, m := range f.Sentinels {
+				var version uint64
+				if _, err := fmt.Sscanf(v, "%d", &version); err != nil {
+					return err
+				}
+				if s.macs[version], err = hex.DecodeString(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	s.key = make([]byte, 32)
+	if _, err := rand.Read(s.key); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *sentinelStore) save(path string) error {
+	if path == "" {
+		return nil // in-memory database, nothing to persist
+	}
+	f := sentinelFile{
+		Key:       hex.EncodeToString(s.key),
+		Sentinels: make(map[string]string, len(s.macs)),
+	}
+	for v, m := range s.macs {
+		f.Sentinels[fmt.Sprintf("%d", v)] = hex.EncodeToString(m)
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// sentinelPath returns the sidecar file path, "" for in-memory databases.
+func (db *DB) sentinelPath() string {
+	if db.path == "" {
+		return ""
+	}
+	return db.path + ".sentinel"
+}
+
+// computeSentinel derives the HMAC-SHA256 over (version || rootHash).
+func (db *DB) computeSentinel(version uint64, key []byte) ([]byte, error) {
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, ErrNotFound
+	}
+	hash, err := root.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], version)
+	mac.Write(v[:])
+	mac.Write(hash[:])
+	return mac.Sum(nil), nil
+}
+
+// WriteSentinel records an HMAC-SHA256 of (version || rootHash) under the
+// database's secret key. Pairing with VerifySentinel gives lightweight
+// tamper detection: flipping stored bytes changes the root hash, and an
+// attacker without the secret cannot forge a matching sentinel. Unlike a
+// full Merkle verification this touches only the root.
+func (db *DB) WriteSentinel(version uint64) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+
+	db.sentinels.mu.Lock()
+	defer db.sentinels.mu.Unlock()
+	if err := db.sentinels.load(db.sentinelPath()); err != nil {
+		return err
+	}
+
+	mac, err := db.computeSentinel(version, db.sentinels.key)
+	if err != nil {
+		return err
+	}
+	db.sentinels.macs[version] = mac
+	return db.sentinels.save(db.sentinelPath())
+}
+
+// VerifySentinel recomputes the sentinel for a version and checks it
+// against the recorded one. Returns ErrNotFound if no sentinel was
+// written for the version and ErrSentinelMismatch if the state changed
+// since WriteSentinel.
+func (db *DB) VerifySentinel(version uint64) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+
+	db.sentinels.mu.Lock()
+	defer db.sentinels.mu.Unlock()
+	if err := db.sentinels.load(db.sentinelPath()); err != nil {
+		return err
+	}
+
+	recorded, ok := db.sentinels.macs[version]
+	if !ok {
+		return ErrNotFound
+	}
+	mac, err := db.computeSentinel(version, db.sentinels.key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(mac, recorded) {
+		return ErrSentinelMismatch
+	}
+	return nil
+}
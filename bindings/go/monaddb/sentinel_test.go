@@ -0,0 +1,36 @@
+package monaddb
+
+import "testing"
+
+// TestSentinel tests writing and verifying tamper-detection sentinels.
+func TestSentinel(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/sentinel.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if err := db.WriteSentinel(1); err != nil {
+		t.Fatalf("WriteSentinel failed: %v", err)
+	}
+	if err := db.VerifySentinel(1); err != nil {
+		t.Errorf("VerifySentinel should pass on untouched state: %v", err)
+	}
+
+	// No sentinel recorded for version 2
+	if err := db.VerifySentinel(2); err != ErrNotFound {
+		t.Errorf("VerifySentinel without a record should return ErrNotFound, got %v", err)
+	}
+
+	// Changing version 1's state invalidates its sentinel
+	if _, err := db.Put(nil, makeKey32(0x02), []byte("tamper"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := db.VerifySentinel(1); err != ErrSentinelMismatch {
+		t.Errorf("VerifySentinel after state change should return ErrSentinelMismatch, got %v", err)
+	}
+}
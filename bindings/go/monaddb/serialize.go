@@ -0,0 +1,106 @@
+package monaddb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// snapshotMagic identifies a serialized trie snapshot.
+var snapshotMagic = [4]byte{'N', 'D', 'B', 'S'}
+
+// snapshotFormatVersion is the snapshot layout this library writes. Future
+// layouts bump the number; Deserialize rejects versions it does not know,
+// so an old reader fails cleanly on a new snapshot instead of misparsing.
+const snapshotFormatVersion uint16 = 1
+
+// Serialize encodes the full trie at root into a self-contained byte
+// slice: format version, trie version, and every key-value pair in key
+// order. The output can cross a process boundary — say from an execution
+// engine to a storage daemon — and be reconstructed with Deserialize.
+func (db *DB) Serialize(root *Node, version uint64) ([]byte, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var payload bytes.Buffer
+	payload.Write(snapshotMagic[:])
+	binary.Write(&payload, binary.BigEndian, snapshotFormatVersion)
+	binary.Write(&payload, binary.BigEndian, version)
+
+	countAt := payload.Len()
+	binary.Write(&payload, binary.BigEndian, uint32(0)) // entry count, patched below
+
+	count := uint32(0)
+	err := db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		binary.Write(&payload, binary.BigEndian, uint32(len(kv.Key)))
+		payload.Write(kv.Key)
+		binary.Write(&payload, binary.BigEndian, uint32(len(kv.Value)))
+		payload.Write(kv.Value)
+		count++
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := payload.Bytes()
+	binary.BigEndian.PutUint32(data[countAt:], count)
+	return data, nil
+}
+
+// Deserialize reconstructs an in-memory database from a snapshot produced
+// by Serialize, returning the database and the root of the restored trie
+// at the snapshot's recorded version. The caller owns the database and
+// must Close it.
+func Deserialize(data []byte) (*DB, *Node, error) {
+	if len(data) < len(snapshotMagic)+2+8+4 {
+		return nil, nil, fmt.Errorf("monaddb: snapshot too short: %w", ErrInvalidArgument)
+	}
+	if !bytes.Equal(data[:4], snapshotMagic[:]) {
+		return nil, nil, fmt.Errorf("monaddb: bad snapshot magic: %w", ErrInvalidArgument)
+	}
+	if format := binary.BigEndian.Uint16(data[4:]); format != snapshotFormatVersion {
+		return nil, nil, fmt.Errorf("monaddb: unsupported snapshot format %d: %w", format, ErrNotSupported)
+	}
+	version := binary.BigEndian.Uint64(data[6:])
+	count := int(binary.BigEndian.Uint32(data[14:]))
+	off := 18
+
+	updates := make([]Update, 0, count)
+	for i := 0; i < count; i++ {
+		if off+4 > len(data) {
+			return nil, nil, fmt.Errorf("monaddb: truncated snapshot entry: %w", ErrInvalidArgument)
+		}
+		keyLen := int(binary.BigEndian.Uint32(data[off:]))
+		off += 4
+		if off+keyLen+4 > len(data) {
+			return nil, nil, fmt.Errorf("monaddb: truncated snapshot key: %w", ErrInvalidArgument)
+		}
+		key := data[off : off+keyLen]
+		off += keyLen
+		valueLen := int(binary.BigEndian.Uint32(data[off:]))
+		off += 4
+		if off+valueLen > len(data) {
+			return nil, nil, fmt.Errorf("monaddb: truncated snapshot value: %w", ErrInvalidArgument)
+		}
+		value := data[off : off+valueLen]
+		off += valueLen
+
+		updates = append(updates, Update{
+			Key:   append([]byte(nil), key...),
+			Value: append([]byte(nil), value...),
+		})
+	}
+
+	db, err := OpenMemory()
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err := db.Upsert(nil, updates, version)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, root, nil
+}
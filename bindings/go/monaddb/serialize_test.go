@@ -0,0 +1,76 @@
+package monaddb
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSerializeRoundTrip tests that a snapshot reconstructs the same trie.
+func TestSerializeRoundTrip(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Key: makeKey32(0x01), Value: []byte("one")},
+		{Key: makeKey32(0x02), Value: []byte("two")},
+		{Key: makeKey32(0x03), Value: []byte("three")},
+	}
+	root, err := db.Upsert(nil, updates, 7)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	data, err := db.Serialize(root, 7)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	restored, restoredRoot, err := Deserialize(data)
+	if err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	defer restored.Close()
+
+	wantHash, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	gotHash, err := restoredRoot.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if wantHash != gotHash {
+		t.Error("Restored root hash should match the original")
+	}
+
+	node, err := restored.FindFromRoot(restoredRoot, makeKey32(0x02), 7)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "two" {
+		t.Errorf("Restored value should be %q, got %q, %v", "two", value, err)
+	}
+}
+
+// TestDeserializeRejectsBadInput tests magic and format-version checks.
+func TestDeserializeRejectsBadInput(t *testing.T) {
+	if _, _, err := Deserialize([]byte("short")); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Short input should fail with ErrInvalidArgument, got %v", err)
+	}
+
+	bad := make([]byte, 32)
+	copy(bad, "XXXX")
+	if _, _, err := Deserialize(bad); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Bad magic should fail with ErrInvalidArgument, got %v", err)
+	}
+
+	future := make([]byte, 32)
+	copy(future, snapshotMagic[:])
+	future[4] = 0xFF // unknown format version
+	if _, _, err := Deserialize(future); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Unknown format should fail with ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package monaddb
+
+import (
+	"bytes"
+	"sort"
+)
+
+// ConflictResolver decides the canonical value for a key that appears
+// with differing values in more than one trie being merged. values holds
+// one entry per root containing the key, in root order. Returning
+// ErrConflict (or any other error) aborts the merge.
+type ConflictResolver func(key []byte, values [][]byte) ([]byte, error)
+
+// Merge combines several partial tries into one new trie at version.
+// Keys unique to one root are copied as-is. For keys present in multiple
+// roots with identical values, the value is copied without consulting the
+// resolver; differing values are passed to resolver to pick the winner.
+//
+// This is the consolidation step after parallel transaction execution,
+// where each worker produced a partial trie of its own writes.
+func (db *DB) Merge(roots []*Node, version uint64, resolver ConflictResolver) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	merged, order, err := db.collectRoots(roots, version)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]Update, 0, len(order))
+	for _, key := range order {
+		values := merged[key]
+		value := values[0]
+		if len(values) > 1 && !allEqual(values) {
+			if resolver == nil {
+				return nil, ErrConflict
+			}
+			value, err = resolver([]byte(key), values)
+			if err != nil {
+				return nil, err
+			}
+		}
+		updates = append(updates, Update{Type: UpdatePut, Key: []byte(key), Value: value})
+	}
+
+	return db.upsertAll(updates, version)
+}
+
+// Union returns a new trie at version containing every key from every
+// input root, the dual of Intersect. Keys appearing in multiple roots
+// with differing values are settled by resolver, exactly as in Merge;
+// Union exists so call sites choosing between set operations read
+// uniformly.
+func (db *DB) Union(roots []*Node, resolver ConflictResolver, version uint64) (*Node, error) {
+	return db.Merge(roots, version, resolver)
+}
+
+// Intersect returns a new trie at version containing only the keys
+// present in every input root, with values taken from roots[0]. With set
+// semantics this computes the common state between chain branches; keys
+// whose values diverge between branches still intersect, so follow up
+// with a value-level comparison where that matters.
+func (db *DB) Intersect(roots []*Node, version uint64) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if len(roots) == 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	merged, order, err := db.collectRoots(roots, version)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]Update, 0, len(order))
+	for _, key := range order {
+		values := merged[key]
+		// collectRoots appends one entry per root holding the key, so a
+		// key in every root has exactly len(roots) values
+		if len(values) == len(roots) {
+			updates = append(updates, Update{Type: UpdatePut, Key: []byte(key), Value: values[0]})
+		}
+	}
+
+	return db.upsertAll(updates, version)
+}
+
+// Subtract returns a new trie at version holding base minus every key
+// present in subtract, regardless of the values stored there. Given a
+// post-block state and the block's write set, this recovers the state
+// without those writes.
+//
+// The deletions are applied on top of base, so unrelated keys keep their
+// existing nodes rather than being rebuilt.
+func (db *DB) Subtract(base, subtract *Node, version uint64) (*Node, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	root := base
+	batch := make([]Update, 0, defragBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		r, err := db.Upsert(root, batch, version)
+		if err != nil {
+			return err
+		}
+		root = r
+		batch = batch[:0]
+		return nil
+	}
+
+	err := db.forEach(subtract, nil, version, func(kv kvEntry) (bool, error) {
+		// Deleting keys absent from base is a no-op in the trie, so no
+		// existence check against base is needed
+		batch = append(batch, Update{Type: UpdateDelete, Key: kv.Key})
+		if len(batch) < defragBatchSize {
+			return true, nil
+		}
+		return true, flush()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// collectRoots walks every root and gathers each key's values in root
+// order, returning the values keyed by key plus the sorted key order.
+func (db *DB) collectRoots(roots []*Node, version uint64) (map[string][][]byte, []string, error) {
+	merged := make(map[string][][]byte)
+	for _, root := range roots {
+		err := db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+			merged[string(kv.Key)] = append(merged[string(kv.Key)], kv.Value)
+			return true, nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	order := make([]string, 0, len(merged))
+	for key := range merged {
+		order = append(order, key)
+	}
+	sort.Strings(order)
+	return merged, order, nil
+}
+
+// upsertAll builds a fresh trie from updates in bounded batches.
+func (db *DB) upsertAll(updates []Update, version uint64) (*Node, error) {
+	var root *Node
+	for len(updates) > 0 {
+		batch := updates
+		if len(batch) > defragBatchSize {
+			batch = batch[:defragBatchSize]
+		}
+		updates = updates[len(batch):]
+
+		r, err := db.Upsert(root, batch, version)
+		if err != nil {
+			return nil, err
+		}
+		root = r
+	}
+	return root, nil
+}
+
+func allEqual(values [][]byte) bool {
+	for _, v := range values[1:] {
+		if !bytes.Equal(v, values[0]) {
+			return false
+		}
+	}
+	return true
+}
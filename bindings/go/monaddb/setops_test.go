@@ -0,0 +1,194 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// buildTrie constructs a fresh trie from the given key-value pairs.
+func buildTrie(t *testing.T, db *DB, version uint64, kvs map[byte]string) *Node {
+	t.Helper()
+	var root *Node
+	var err error
+	for k, v := range kvs {
+		root, err = db.Put(root, makeKey32(k), []byte(v), version)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	return root
+}
+
+// lookupString reads a key's value as a string, "" if absent.
+func lookupString(t *testing.T, db *DB, root *Node, k byte, version uint64) string {
+	t.Helper()
+	node, err := db.FindFromRoot(root, makeKey32(k), version)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	if node == nil || !node.HasValue() {
+		return ""
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	return string(value)
+}
+
+// TestMerge tests merging partial tries with a conflict resolver.
+func TestMerge(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	a := buildTrie(t, db, 1, map[byte]string{0x01: "one", 0x02: "a-two"})
+	b := buildTrie(t, db, 1, map[byte]string{0x02: "b-two", 0x03: "three"})
+
+	var resolved int
+	merged, err := db.Merge([]*Node{a, b}, 1, func(key []byte, values [][]byte) ([]byte, error) {
+		resolved++
+		if len(values) != 2 {
+			t.Errorf("Resolver should see 2 values, got %d", len(values))
+		}
+		return values[1], nil // last writer wins
+	})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if resolved != 1 {
+		t.Errorf("Resolver should run once (only key 0x02 conflicts), ran %d times", resolved)
+	}
+
+	if got := lookupString(t, db, merged, 0x01, 1); got != "one" {
+		t.Errorf("Key 0x01 should be %q, got %q", "one", got)
+	}
+	if got := lookupString(t, db, merged, 0x02, 1); got != "b-two" {
+		t.Errorf("Key 0x02 should resolve to %q, got %q", "b-two", got)
+	}
+	if got := lookupString(t, db, merged, 0x03, 1); got != "three" {
+		t.Errorf("Key 0x03 should be %q, got %q", "three", got)
+	}
+}
+
+// TestUnion tests set-union over tries.
+func TestUnion(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	a := buildTrie(t, db, 1, map[byte]string{0x01: "one", 0x02: "a-two"})
+	b := buildTrie(t, db, 1, map[byte]string{0x02: "b-two", 0x03: "three"})
+
+	union, err := db.Union([]*Node{a, b}, func(key []byte, values [][]byte) ([]byte, error) {
+		return values[0], nil // first writer wins
+	}, 1)
+	if err != nil {
+		t.Fatalf("Union failed: %v", err)
+	}
+
+	want := map[byte]string{0x01: "one", 0x02: "a-two", 0x03: "three"}
+	for k, v := range want {
+		if got := lookupString(t, db, union, k, 1); got != v {
+			t.Errorf("Key %#x should be %q, got %q", k, v, got)
+		}
+	}
+}
+
+// TestIntersect tests set-intersection over tries.
+func TestIntersect(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	a := buildTrie(t, db, 1, map[byte]string{0x01: "one", 0x02: "a-two", 0x03: "three"})
+	b := buildTrie(t, db, 1, map[byte]string{0x02: "b-two", 0x03: "three", 0x04: "four"})
+
+	common, err := db.Intersect([]*Node{a, b}, 1)
+	if err != nil {
+		t.Fatalf("Intersect failed: %v", err)
+	}
+
+	// Values come from the first root
+	if got := lookupString(t, db, common, 0x02, 1); got != "a-two" {
+		t.Errorf("Key 0x02 should take roots[0] value %q, got %q", "a-two", got)
+	}
+	if got := lookupString(t, db, common, 0x03, 1); got != "three" {
+		t.Errorf("Key 0x03 should be %q, got %q", "three", got)
+	}
+	for _, k := range []byte{0x01, 0x04} {
+		if got := lookupString(t, db, common, k, 1); got != "" {
+			t.Errorf("Key %#x should not be in the intersection, got %q", k, got)
+		}
+	}
+
+	if _, err := db.Intersect(nil, 1); err != ErrInvalidArgument {
+		t.Errorf("Intersect of no roots should return ErrInvalidArgument, got %v", err)
+	}
+}
+
+// TestSubtract tests set-difference over tries.
+func TestSubtract(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	base := buildTrie(t, db, 1, map[byte]string{0x01: "one", 0x02: "two", 0x03: "three"})
+	writes := buildTrie(t, db, 1, map[byte]string{0x02: "anything", 0x04: "not in base"})
+
+	result, err := db.Subtract(base, writes, 1)
+	if err != nil {
+		t.Fatalf("Subtract failed: %v", err)
+	}
+
+	if got := lookupString(t, db, result, 0x01, 1); got != "one" {
+		t.Errorf("Key 0x01 should survive, got %q", got)
+	}
+	if got := lookupString(t, db, result, 0x03, 1); got != "three" {
+		t.Errorf("Key 0x03 should survive, got %q", got)
+	}
+	if got := lookupString(t, db, result, 0x02, 1); got != "" {
+		t.Errorf("Key 0x02 should be removed, got %q", got)
+	}
+}
+
+// TestMergeConflictAborts tests that a resolver error aborts the merge.
+func TestMergeConflictAborts(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	a := buildTrie(t, db, 1, map[byte]string{0x01: "a"})
+	b := buildTrie(t, db, 1, map[byte]string{0x01: "b"})
+
+	_, err = db.Merge([]*Node{a, b}, 1, func([]byte, [][]byte) ([]byte, error) {
+		return nil, ErrConflict
+	})
+	if err != ErrConflict {
+		t.Errorf("Merge should abort with ErrConflict, got %v", err)
+	}
+
+	// A nil resolver rejects any conflict
+	if _, err := db.Merge([]*Node{a, b}, 1, nil); err != ErrConflict {
+		t.Errorf("Merge without resolver should return ErrConflict, got %v", err)
+	}
+
+	// Identical values need no resolver
+	c := buildTrie(t, db, 1, map[byte]string{0x01: "a"})
+	merged, err := db.Merge([]*Node{a, c}, 1, nil)
+	if err != nil {
+		t.Fatalf("Merge of identical tries failed: %v", err)
+	}
+	if got := lookupString(t, db, merged, 0x01, 1); got != "a" {
+		t.Errorf("Key 0x01 should be %q, got %q", "a", got)
+	}
+}
@@ -0,0 +1,108 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+*/
+import "C"
+import "unsafe"
+
+// ChildMask returns the bitmask of populated child branches: bit i set
+// means the node has a child at branch nibble i.
+func (n *Node) ChildMask() uint16 {
+	if n == nil || n.ptr == nil {
+		return 0
+	}
+	return uint16(C.nomad_node_child_mask(n.ptr))
+}
+
+// ChildHash returns the Merkle hash of the child at the given branch
+// nibble, read from the parent's cached child data without resolving the
+// child. Empty branches return ErrNotFound.
+func (n *Node) ChildHash(branch byte) ([32]byte, error) {
+	var hash [32]byte
+	if n == nil || n.ptr == nil {
+		return hash, ErrNullPointer
+	}
+	code := C.nomad_node_child_hash(n.ptr, C.uint8_t(branch), (*C.uint8_t)(unsafe.Pointer(&hash[0])))
+	if err := codeToError(int(code)); err != nil {
+		return hash, err
+	}
+	return hash, nil
+}
+
+// FindWithSiblingHashes looks a key up and returns, alongside its value,
+// a 256-slot sibling table in the binary-trie layout proof systems with
+// bit-level commitments consume. Each branch decision along the descent
+// is decomposed into its four bits, MSB first: slot 4j+k holds the
+// sibling for bit k of the j-th branch decision, the keccak256 of the
+// cached child hashes (in branch order) of the children that agree with
+// the taken branch on the bits above k and differ at k. A bit whose
+// sibling group is empty keeps a zero hash, as do slots beyond the
+// descent — path compression consumes key bits without a branch decision
+// and contributes no siblings. An absent key returns ErrNotFound.
+func (db *DB) FindWithSiblingHashes(root *Node, key []byte, version uint64) (value []byte, siblings [][32]byte, err error) {
+	if db == nil || db.ptr == nil {
+		return nil, nil, ErrNullPointer
+	}
+
+	node, err := db.FindFromRoot(root, key, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if node == nil {
+		return nil, nil, ErrNotFound
+	}
+	if value, err = node.Value(); err != nil {
+		return nil, nil, err
+	}
+
+	siblings = make([][32]byte, 256)
+	var parent *Node
+	branchDepth := 0
+	err = db.WalkPath(root, key, version, func(step *Node, depth int, nibble byte) error {
+		if parent != nil {
+			if branchDepth < 64 {
+				db.siblingBits(parent, nibble, siblings[branchDepth*4:branchDepth*4+4])
+			}
+			branchDepth++
+			parent.Free()
+		}
+		parent = step.Clone()
+		return nil
+	})
+	if parent != nil {
+		parent.Free()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, siblings, nil
+}
+
+// siblingBits fills the four bit-level sibling slots for one branch
+// decision: taken from a node whose other children are grouped by the
+// first bit at which their branch diverges from the chosen one.
+func (db *DB) siblingBits(parent *Node, taken byte, slots [][32]byte) {
+	mask := parent.ChildMask()
+	for bit := 0; bit < 4; bit++ {
+		// Branches matching the taken nibble above this bit, differing at it
+		shift := 3 - bit
+		var group []byte
+		for branch := byte(0); branch < 16; branch++ {
+			if mask&(1<<branch) == 0 || branch == taken {
+				continue
+			}
+			if (branch>>(shift+1)) == (taken>>(shift+1)) &&
+				(branch>>shift)&1 != (taken>>shift)&1 {
+				hash, err := parent.ChildHash(branch)
+				if err != nil {
+					continue
+				}
+				group = append(group, hash[:]...)
+			}
+		}
+		if len(group) > 0 {
+			slots[bit] = db.KeyHash(group)
+		}
+	}
+}
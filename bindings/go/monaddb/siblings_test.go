@@ -0,0 +1,80 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestChildMaskAndHash tests the cached child accessors on a branch node.
+func TestChildMaskAndHash(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Upsert(nil, []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x21), Value: []byte("two")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	mask := root.ChildMask()
+	if mask&(1<<1) == 0 || mask&(1<<2) == 0 {
+		t.Errorf("Root should branch at nibbles 1 and 2, mask %#x", mask)
+	}
+
+	hash, err := root.ChildHash(1)
+	if err != nil {
+		t.Fatalf("ChildHash failed: %v", err)
+	}
+	if hash == ([32]byte{}) {
+		t.Error("A populated branch should have a non-zero child hash")
+	}
+	if _, err := root.ChildHash(7); err != ErrNotFound {
+		t.Errorf("An empty branch should return ErrNotFound, got %v", err)
+	}
+}
+
+// TestFindWithSiblingHashes tests the binary-layout sibling table.
+func TestFindWithSiblingHashes(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Upsert(nil, []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x21), Value: []byte("two")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	value, siblings, err := db.FindWithSiblingHashes(root, makeKey32(0x11), 1)
+	if err != nil {
+		t.Fatalf("FindWithSiblingHashes failed: %v", err)
+	}
+	if string(value) != "one" {
+		t.Errorf("Expected the key's value, got %q", value)
+	}
+	if len(siblings) != 256 {
+		t.Fatalf("Expected 256 sibling slots, got %d", len(siblings))
+	}
+
+	// The first branch decision separates nibble 1 from nibble 2: they
+	// share bits 00, differ at the third bit, so slot 2 carries the
+	// sibling and the other slots of that decision stay zero
+	if siblings[2] == ([32]byte{}) {
+		t.Error("The diverging bit should carry a sibling hash")
+	}
+	if siblings[0] != ([32]byte{}) || siblings[1] != ([32]byte{}) || siblings[3] != ([32]byte{}) {
+		t.Error("Bits without diverging children should stay zero")
+	}
+
+	if _, _, err := db.FindWithSiblingHashes(root, makeKey32(0x99), 1); err != ErrNotFound {
+		t.Errorf("An absent key should return ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package monaddb
+
+// Simulate applies updates as a dry run: the upsert executes against an
+// ephemeral clone of the database, so nothing is persisted and the
+// caller's version history is untouched. It returns the root the commit
+// would produce — useful for checking the resulting state hash against a
+// proposal — together with the operation statistics the run accumulated.
+// The returned root keeps the ephemeral clone alive until it is released.
+// Disk databases cannot be cloned and return ErrNotSupported.
+func (db *DB) Simulate(root *Node, updates []Update, version uint64) (*Node, Stats, error) {
+	if db.ptr == nil {
+		return nil, Stats{}, ErrNullPointer
+	}
+
+	clone, err := db.Clone()
+	if err != nil {
+		return nil, Stats{}, err
+	}
+
+	newRoot, err := clone.Upsert(root, updates, version)
+	stats := clone.Stats()
+	if err != nil {
+		clone.Close()
+		return nil, stats, err
+	}
+	if newRoot == nil {
+		clone.Close()
+		return nil, stats, nil
+	}
+
+	// The node's back-reference keeps the clone reachable; its finalizer
+	// closes the clone once the caller drops the root.
+	return newRoot, stats, nil
+}
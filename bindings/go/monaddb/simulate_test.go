@@ -0,0 +1,52 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestSimulate tests that a dry run reports the commit's outcome without
+// changing the database.
+func TestSimulate(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("base"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	updates := []Update{
+		{Key: makeKey32(0x02), Value: []byte("new")},
+		{Key: makeKey32(0x01), Type: UpdateDelete},
+	}
+	simRoot, stats, err := db.Simulate(root, updates, 2)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if stats.WriteOps != 1 || stats.DeleteOps != 1 {
+		t.Errorf("Stats should reflect the dry run, got %+v", stats)
+	}
+
+	// The simulated root shows the would-be state
+	if _, err := db.FindFromRoot(simRoot, makeKey32(0x02), 2); err != nil {
+		t.Errorf("Simulated root should contain the new key: %v", err)
+	}
+
+	// The original database is untouched
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "base" {
+		t.Errorf("Original state should be intact, got %q, %v", value, err)
+	}
+	if leaked, err := db.FindFromRoot(root, makeKey32(0x02), 1); err != nil || leaked != nil {
+		t.Errorf("Original root should not see the simulated write, got %v, %v", leaked, err)
+	}
+	if got := db.Stats(); got.WriteOps != 1 {
+		t.Errorf("Dry-run stats should not leak into the original, got %+v", got)
+	}
+}
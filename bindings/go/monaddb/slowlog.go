@@ -0,0 +1,60 @@
+package monaddb
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// slowLogConfig holds the slow-log destination and threshold. Entries are
+// written under the mutex so concurrent slow operations emit whole lines.
+type slowLogConfig struct {
+	mu        sync.Mutex
+	w         io.Writer
+	threshold time.Duration
+}
+
+// slowLogEntry is the JSON line layout of one slow-log record.
+type slowLogEntry struct {
+	Op         string `json:"op"`
+	Key        string `json:"key"`
+	Version    uint64 `json:"version"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// SetSlowLog streams every operation slower than threshold to w as one
+// JSON line, for example:
+//
+//	{"op":"find","key":"0x0142...","version":42,"duration_ms":150}
+//
+// Unlike SetSlowOpThreshold's callback, the slow log needs no code on the
+// consumer side — point it at a file and grep it later. Both mechanisms
+// can be active at once. A nil writer or a threshold <= 0 disables the
+// log.
+func (db *DB) SetSlowLog(w io.Writer, threshold time.Duration) {
+	if w == nil || threshold <= 0 {
+		db.slowLog.Store(nil)
+		return
+	}
+	db.slowLog.Store(&slowLogConfig{w: w, threshold: threshold})
+}
+
+// log emits one slow-operation record.
+func (cfg *slowLogConfig) log(op string, key []byte, version uint64, elapsed time.Duration) {
+	line, err := json.Marshal(slowLogEntry{
+		Op:         op,
+		Key:        "0x" + hex.EncodeToString(key),
+		Version:    version,
+		DurationMS: elapsed.Milliseconds(),
+	})
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	cfg.mu.Lock()
+	cfg.w.Write(line)
+	cfg.mu.Unlock()
+}
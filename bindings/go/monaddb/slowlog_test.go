@@ -0,0 +1,80 @@
+package monaddb
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// sleepyCompressor artificially slows the write path so the slow log has
+// something to catch.
+type sleepyCompressor struct {
+	delay time.Duration
+}
+
+func (c sleepyCompressor) Compress(dst, src []byte) ([]byte, error) {
+	time.Sleep(c.delay)
+	return src, nil
+}
+
+func (c sleepyCompressor) Decompress(dst, src []byte) ([]byte, error) {
+	return src, nil
+}
+
+// TestSetSlowLog tests that slow operations land in the log as JSON lines.
+func TestSetSlowLog(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var log bytes.Buffer
+	db.SetSlowLog(&log, 20*time.Millisecond)
+	db.SetCompressor(sleepyCompressor{delay: 50 * time.Millisecond})
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 42); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if log.Len() == 0 {
+		t.Fatal("Slow write should be logged")
+	}
+	var entry slowLogEntry
+	if err := json.Unmarshal(bytes.Split(log.Bytes(), []byte("\n"))[0], &entry); err != nil {
+		t.Fatalf("Log line should be valid JSON: %v", err)
+	}
+	if entry.Op != "upsert" {
+		t.Errorf("Expected op upsert, got %q", entry.Op)
+	}
+	if entry.Version != 42 {
+		t.Errorf("Expected version 42, got %d", entry.Version)
+	}
+	if entry.DurationMS < 20 {
+		t.Errorf("Duration should exceed the threshold, got %dms", entry.DurationMS)
+	}
+	if len(entry.Key) < 2 || entry.Key[:2] != "0x" {
+		t.Errorf("Key should be hex-encoded, got %q", entry.Key)
+	}
+
+	// Fast operations stay out of the log
+	log.Reset()
+	db.SetCompressor(nil)
+	if _, err := db.Put(nil, makeKey32(0x02), []byte("value"), 43); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if log.Len() != 0 {
+		t.Errorf("Fast write should not be logged, got %q", log.String())
+	}
+
+	// Disabling stops logging entirely
+	db.SetSlowLog(nil, 0)
+	db.SetCompressor(sleepyCompressor{delay: 50 * time.Millisecond})
+	if _, err := db.Put(nil, makeKey32(0x03), []byte("value"), 44); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if log.Len() != 0 {
+		t.Error("Disabled slow log should stay silent")
+	}
+}
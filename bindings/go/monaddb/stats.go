@@ -0,0 +1,467 @@
+package monaddb
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// KeyFrequency reports how often a key was read within the sampling window.
+type KeyFrequency struct {
+	Key   []byte
+	Count uint64
+}
+
+// keyFreqTracker maintains a sliding window of recently read keys and their
+// frequencies. The window is a ring buffer: once full, recording a new read
+// evicts the oldest one so counts always reflect the last windowSize reads.
+type keyFreqTracker struct {
+	mu     sync.Mutex
+	window []string // ring buffer of recently read keys
+	next   int
+	full   bool
+	counts map[string]uint64
+}
+
+func newKeyFreqTracker(windowSize int) *keyFreqTracker {
+	return &keyFreqTracker{
+		window: make([]string, windowSize),
+		counts: make(map[string]uint64),
+	}
+}
+
+func (t *keyFreqTracker) record(key []byte) {
+	k := string(key)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.full {
+		// Evict the oldest read from the counts
+		old := t.window[t.next]
+		if n := t.counts[old]; n <= 1 {
+			delete(t.counts, old)
+		} else {
+			t.counts[old] = n - 1
+		}
+	}
+
+	t.window[t.next] = k
+	t.counts[k]++
+	t.next++
+	if t.next == len(t.window) {
+		t.next = 0
+		t.full = true
+	}
+}
+
+func (t *keyFreqTracker) topK(k int) []KeyFrequency {
+	t.mu.Lock()
+	result := make([]KeyFrequency, 0, len(t.counts))
+	for key, count := range t.counts {
+		result = append(result, KeyFrequency{Key: []byte(key), Count: count})
+	}
+	t.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	if k < len(result) {
+		result = result[:k]
+	}
+	return result
+}
+
+// EnableKeyFrequency activates a sliding-window frequency counter for read
+// operations. windowSize is the number of most recent reads the counter
+// covers; a windowSize <= 0 disables tracking again.
+//
+// Tracking is disabled by default because the window adds memory overhead
+// proportional to windowSize.
+func (db *DB) EnableKeyFrequency(windowSize int) {
+	if windowSize <= 0 {
+		db.keyFreq.Store(nil)
+		return
+	}
+	db.keyFreq.Store(newKeyFreqTracker(windowSize))
+}
+
+// TopKKeys returns the k most-read keys within the sampling window, most
+// frequent first. Returns nil if key-frequency tracking is not enabled.
+func (db *DB) TopKKeys(k int) []KeyFrequency {
+	tracker := db.keyFreq.Load()
+	if tracker == nil || k <= 0 {
+		return nil
+	}
+	return tracker.topK(k)
+}
+
+// KeyAgeCDF returns a cumulative distribution of key insertion recency.
+//
+// The age of a key is how many versions ago it was last written, measured
+// from the database's latest version. The version span between the earliest
+// and latest version is divided into the given number of buckets, and
+// bucket[i] is the fraction of keys whose age exceeds
+// i * span / buckets. bucket[0] is therefore always 1 for a non-empty trie,
+// and later buckets answer "what fraction of keys are older than X?",
+// which guides history compaction decisions.
+//
+// An empty trie yields all-zero buckets.
+func (db *DB) KeyAgeCDF(root *Node, version uint64, buckets int) ([]float64, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if buckets <= 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	latest := db.LatestVersion()
+	span := latest - db.EarliestVersion()
+
+	var total uint64
+	ages := make([]uint64, 0, 1024)
+	err := db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		var age uint64
+		if kv.Version < latest {
+			age = latest - kv.Version
+		}
+		ages = append(ages, age)
+		total++
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cdf := make([]float64, buckets)
+	if total == 0 {
+		return cdf, nil
+	}
+
+	for i := 0; i < buckets; i++ {
+		threshold := uint64(i) * span / uint64(buckets)
+		var older uint64
+		for _, age := range ages {
+			if age > threshold {
+				older++
+			}
+		}
+		if i == 0 {
+			// Age 0 keys are still "at least 0 versions old"
+			older = total
+		}
+		cdf[i] = float64(older) / float64(total)
+	}
+	return cdf, nil
+}
+
+// HotKey reports cumulative access counts for a single key.
+type HotKey struct {
+	Key    []byte
+	Reads  uint64
+	Writes uint64
+}
+
+// hotKeyCounter accumulates per-key read and write counts from every
+// FindFromRoot and Upsert call.
+type hotKeyCounter struct {
+	mu     sync.Mutex
+	counts map[string]*HotKey
+}
+
+func (c *hotKeyCounter) bump(key []byte, read bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts == nil {
+		c.counts = make(map[string]*HotKey)
+	}
+	k := string(key)
+	hk := c.counts[k]
+	if hk == nil {
+		hk = &HotKey{Key: []byte(key)}
+		c.counts[k] = hk
+	}
+	if read {
+		hk.Reads++
+	} else {
+		hk.Writes++
+	}
+}
+
+// hotKeyHeap is a min-heap over total access count, used to keep only the
+// top-n keys while scanning the counter map.
+type hotKeyHeap []HotKey
+
+func (h hotKeyHeap) Len() int { return len(h) }
+
+func (h hotKeyHeap) Less(i, j int) bool {
+	return h[i].Reads+h[i].Writes < h[j].Reads+h[j].Writes
+}
+
+func (h hotKeyHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *hotKeyHeap) Push(x any) { *h = append(*h, x.(HotKey)) }
+
+func (h *hotKeyHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// HotKeys returns the n most frequently accessed keys, hottest first.
+// Both reads (FindFromRoot) and writes (Upsert) count towards hotness.
+func (db *DB) HotKeys(n int) ([]HotKey, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if n <= 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	db.hotKeys.mu.Lock()
+	h := make(hotKeyHeap, 0, n)
+	for _, hk := range db.hotKeys.counts {
+		if len(h) < n {
+			heap.Push(&h, *hk)
+			continue
+		}
+		if hk.Reads+hk.Writes > h[0].Reads+h[0].Writes {
+			h[0] = *hk
+			heap.Fix(&h, 0)
+		}
+	}
+	db.hotKeys.mu.Unlock()
+
+	// Drain the min-heap into descending order
+	result := make([]HotKey, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(&h).(HotKey)
+	}
+	return result, nil
+}
+
+// Stats holds cumulative operation counters for a database. Counters
+// accumulate from DB creation (or the last ResetStats call).
+type Stats struct {
+	ReadOps      uint64 // Find / FindFromRoot calls
+	WriteOps     uint64 // Put-type updates applied via Upsert
+	DeleteOps    uint64 // Delete-type updates applied via Upsert
+	BytesWritten uint64 // Total key + value bytes submitted for writing
+}
+
+// statsCounters guards the counters with a mutex so that ResetStats and
+// SnapshotStats are atomic: a concurrent reader sees either all-old or
+// all-new values, never a mix.
+type statsCounters struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Stats returns a snapshot of the cumulative operation counters.
+func (db *DB) Stats() Stats {
+	db.stats.mu.Lock()
+	defer db.stats.mu.Unlock()
+	return db.stats.stats
+}
+
+// ResetStats atomically zeros all counters returned by Stats.
+func (db *DB) ResetStats() error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	db.stats.mu.Lock()
+	db.stats.stats = Stats{}
+	db.stats.mu.Unlock()
+	return nil
+}
+
+// SnapshotStats returns the current stats and resets them in one atomic
+// call, so no operations are lost between the read and the reset. This is
+// the right primitive for benchmarking a specific phase.
+func (db *DB) SnapshotStats() (Stats, error) {
+	if db == nil || db.ptr == nil {
+		return Stats{}, ErrNullPointer
+	}
+	db.stats.mu.Lock()
+	snapshot := db.stats.stats
+	db.stats.stats = Stats{}
+	db.stats.mu.Unlock()
+	return snapshot, nil
+}
+
+// rollingWindowSeconds is the capacity of the per-second counter ring:
+// RollingStats can look back at most this far.
+const rollingWindowSeconds = 300
+
+// rollingCounters keeps one Stats bucket per second in a ring. A bucket is
+// lazily zeroed the first time a counter lands in its second, so seconds
+// with no traffic cost nothing; stale buckets are filtered out by their
+// recorded timestamp when summing.
+type rollingCounters struct {
+	mu      sync.Mutex
+	buckets [rollingWindowSeconds]Stats
+	seconds [rollingWindowSeconds]int64 // unix second each bucket covers
+}
+
+func (r *rollingCounters) bump(f func(*Stats)) {
+	now := time.Now().Unix()
+
+	r.mu.Lock()
+	i := int(now % rollingWindowSeconds)
+	if r.seconds[i] != now {
+		r.buckets[i] = Stats{}
+		r.seconds[i] = now
+	}
+	f(&r.buckets[i])
+	r.mu.Unlock()
+}
+
+func (r *rollingCounters) sum(seconds int64) Stats {
+	now := time.Now().Unix()
+	cutoff := now - seconds
+
+	var total Stats
+	r.mu.Lock()
+	for i := range r.buckets {
+		if r.seconds[i] > cutoff {
+			total.ReadOps += r.buckets[i].ReadOps
+			total.WriteOps += r.buckets[i].WriteOps
+			total.DeleteOps += r.buckets[i].DeleteOps
+			total.BytesWritten += r.buckets[i].BytesWritten
+		}
+	}
+	r.mu.Unlock()
+	return total
+}
+
+// RollingStats returns the operation counters accumulated during the last
+// window, backed by a ring of per-second buckets. Dividing by the window
+// length gives rates directly — ops/s = stats.ReadOps / window.Seconds() —
+// with no external delta bookkeeping. The window is rounded up to a whole
+// second and capped at five minutes; longer windows return
+// ErrInvalidArgument.
+func (db *DB) RollingStats(window time.Duration) (Stats, error) {
+	if db == nil || db.ptr == nil {
+		return Stats{}, ErrNullPointer
+	}
+	if window <= 0 || window > rollingWindowSeconds*time.Second {
+		return Stats{}, ErrInvalidArgument
+	}
+
+	seconds := int64((window + time.Second - 1) / time.Second)
+	return db.rolling.sum(seconds), nil
+}
+
+// QuiesceStats waits until no operations are executing — reads and writes
+// in flight have returned and the asynchronous write pipeline is empty —
+// then returns a snapshot of the cumulative counters. Taking the snapshot
+// from a quiet database keeps end-of-benchmark numbers free of
+// partial-operation noise. Returns the context error if the database does
+// not go quiet in time; new operations started while waiting extend the
+// wait.
+func (db *DB) QuiesceStats(ctx context.Context) (Stats, error) {
+	if db == nil || db.ptr == nil {
+		return Stats{}, ErrNullPointer
+	}
+
+	for {
+		pendingOps, _, err := db.WriteLag()
+		if err != nil {
+			return Stats{}, err
+		}
+		if db.inflight.Load() == 0 && pendingOps == 0 {
+			return db.Stats(), nil
+		}
+		select {
+		case <-ctx.Done():
+			return Stats{}, ctx.Err()
+		case <-time.After(100 * time.Microsecond):
+		}
+	}
+}
+
+// WriteLag reports the depth of the asynchronous write pipeline.
+//
+// pendingOps is the number of writes accepted but not yet durably applied
+// (for example writes queued by an auto-batcher); pendingBytes is their
+// estimated key + value footprint. Callers implementing back-pressure
+// should pause submissions when pendingOps exceeds their threshold.
+//
+// For the fully synchronous write path both values are always zero.
+func (db *DB) WriteLag() (pendingOps uint64, pendingBytes uint64, err error) {
+	if db == nil || db.ptr == nil {
+		return 0, 0, ErrNullPointer
+	}
+	return db.pendingOps.Load(), db.pendingBytes.Load(), nil
+}
+
+// WriteLagPrometheus writes the write-lag metrics to w in the Prometheus
+// text exposition format, suitable for serving from a /metrics endpoint.
+func (db *DB) WriteLagPrometheus(w io.Writer) error {
+	pendingOps, pendingBytes, err := db.WriteLag()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w,
+		"# HELP monaddb_write_lag_ops Writes accepted but not yet durably committed.\n"+
+			"# TYPE monaddb_write_lag_ops gauge\n"+
+			"monaddb_write_lag_ops %d\n"+
+			"# HELP monaddb_write_lag_bytes Estimated byte footprint of pending writes.\n"+
+			"# TYPE monaddb_write_lag_bytes gauge\n"+
+			"monaddb_write_lag_bytes %d\n",
+		pendingOps, pendingBytes)
+	return err
+}
+
+// addWriteLag adjusts the pending write counters; delta values are applied
+// by async writers when accepting and when durably committing writes.
+func (db *DB) addWriteLag(ops int64, bytes int64) {
+	db.pendingOps.Add(uint64(ops))
+	db.pendingBytes.Add(uint64(bytes))
+}
+
+// recordRead feeds a read key into the access trackers.
+func (db *DB) recordRead(key []byte) {
+	if tracker := db.keyFreq.Load(); tracker != nil {
+		tracker.record(key)
+	}
+	db.hotKeys.bump(key, true)
+	db.rolling.bump(func(s *Stats) { s.ReadOps++ })
+
+	db.stats.mu.Lock()
+	db.stats.stats.ReadOps++
+	db.stats.mu.Unlock()
+}
+
+// recordWrite feeds an update into the access trackers.
+func (db *DB) recordWrite(u *Update) {
+	db.hotKeys.bump(u.Key, false)
+	if u.Type != UpdateDelete {
+		db.logicalWritten.Add(uint64(len(u.Value)))
+	}
+	size := uint64(len(u.Key) + len(u.Value))
+	db.rolling.bump(func(s *Stats) {
+		if u.Type == UpdateDelete {
+			s.DeleteOps++
+		} else {
+			s.WriteOps++
+		}
+		s.BytesWritten += size
+	})
+
+	db.stats.mu.Lock()
+	if u.Type == UpdateDelete {
+		db.stats.stats.DeleteOps++
+	} else {
+		db.stats.stats.WriteOps++
+	}
+	db.stats.stats.BytesWritten += uint64(len(u.Key) + len(u.Value))
+	db.stats.mu.Unlock()
+}
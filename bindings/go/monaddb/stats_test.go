@@ -0,0 +1,290 @@
+package monaddb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestKeyFrequency tests the sliding-window read frequency counter.
+func TestKeyFrequency(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	hot := makeKey32(0xAA)
+	cold := makeKey32(0xBB)
+	root, err := db.Upsert(nil, []Update{
+		{Type: UpdatePut, Key: hot, Value: []byte("hot")},
+		{Type: UpdatePut, Key: cold, Value: []byte("cold")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	db.EnableKeyFrequency(100)
+
+	for i := 0; i < 10; i++ {
+		if _, err := db.FindFromRoot(root, hot, 1); err != nil {
+			t.Fatalf("FindFromRoot failed: %v", err)
+		}
+	}
+	if _, err := db.FindFromRoot(root, cold, 1); err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+
+	top := db.TopKKeys(1)
+	if len(top) != 1 {
+		t.Fatalf("TopKKeys(1) returned %d entries", len(top))
+	}
+	if !bytes.Equal(top[0].Key, hot) {
+		t.Errorf("TopKKeys should rank the hot key first, got %x", top[0].Key)
+	}
+	if top[0].Count != 10 {
+		t.Errorf("Hot key count should be 10, got %d", top[0].Count)
+	}
+
+	// Disabling tracking clears the results
+	db.EnableKeyFrequency(0)
+	if db.TopKKeys(1) != nil {
+		t.Error("TopKKeys should return nil after tracking is disabled")
+	}
+}
+
+// TestHotKeys tests the top-N access frequency ranking.
+func TestHotKeys(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	keyA := makeKey32(0x0A)
+	keyB := makeKey32(0x0B)
+	root, err := db.Upsert(nil, []Update{
+		{Type: UpdatePut, Key: keyA, Value: []byte("a")},
+		{Type: UpdatePut, Key: keyB, Value: []byte("b")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		if _, err := db.FindFromRoot(root, keyA, 1); err != nil {
+			t.Fatalf("FindFromRoot failed: %v", err)
+		}
+	}
+	if _, err := db.FindFromRoot(root, keyB, 1); err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+
+	hot, err := db.HotKeys(1)
+	if err != nil {
+		t.Fatalf("HotKeys failed: %v", err)
+	}
+	if len(hot) != 1 {
+		t.Fatalf("HotKeys(1) returned %d entries", len(hot))
+	}
+	if !bytes.Equal(hot[0].Key, keyA) {
+		t.Errorf("HotKeys should rank key A first, got %x", hot[0].Key)
+	}
+	if hot[0].Reads != 1000 {
+		t.Errorf("Key A reads should be 1000, got %d", hot[0].Reads)
+	}
+	if hot[0].Writes != 1 {
+		t.Errorf("Key A writes should be 1, got %d", hot[0].Writes)
+	}
+}
+
+// TestStatsResetAndSnapshot tests the stats counters and atomic reset.
+func TestStatsResetAndSnapshot(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	root, err := db.Put(nil, key, []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := db.FindFromRoot(root, key, 1); err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+
+	stats := db.Stats()
+	if stats.WriteOps != 1 {
+		t.Errorf("WriteOps should be 1, got %d", stats.WriteOps)
+	}
+	if stats.ReadOps != 1 {
+		t.Errorf("ReadOps should be 1, got %d", stats.ReadOps)
+	}
+	if stats.BytesWritten == 0 {
+		t.Error("BytesWritten should be non-zero")
+	}
+
+	// SnapshotStats returns the counters and resets them atomically
+	snapshot, err := db.SnapshotStats()
+	if err != nil {
+		t.Fatalf("SnapshotStats failed: %v", err)
+	}
+	if snapshot != stats {
+		t.Errorf("Snapshot mismatch: got %+v, want %+v", snapshot, stats)
+	}
+	if after := db.Stats(); after != (Stats{}) {
+		t.Errorf("Stats should be zero after snapshot, got %+v", after)
+	}
+
+	// ResetStats zeros counters
+	if _, err := db.FindFromRoot(root, key, 1); err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	if err := db.ResetStats(); err != nil {
+		t.Fatalf("ResetStats failed: %v", err)
+	}
+	if after := db.Stats(); after != (Stats{}) {
+		t.Errorf("Stats should be zero after reset, got %+v", after)
+	}
+}
+
+// TestWriteLag tests the write pipeline depth metric and its Prometheus export.
+func TestWriteLag(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	// The synchronous write path never leaves writes pending
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	ops, pending, err := db.WriteLag()
+	if err != nil {
+		t.Fatalf("WriteLag failed: %v", err)
+	}
+	if ops != 0 || pending != 0 {
+		t.Errorf("Synchronous writes should leave no lag, got ops=%d bytes=%d", ops, pending)
+	}
+
+	db.addWriteLag(3, 150)
+	var buf bytes.Buffer
+	if err := db.WriteLagPrometheus(&buf); err != nil {
+		t.Fatalf("WriteLagPrometheus failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "monaddb_write_lag_ops 3") {
+		t.Errorf("Prometheus output missing ops gauge: %q", out)
+	}
+	if !strings.Contains(out, "monaddb_write_lag_bytes 150") {
+		t.Errorf("Prometheus output missing bytes gauge: %q", out)
+	}
+	db.addWriteLag(-3, -150)
+}
+
+// TestKeyFrequencyWindowEviction tests that old reads fall out of the window.
+func TestKeyFrequencyWindowEviction(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	a := makeKey32(0x01)
+	b := makeKey32(0x02)
+	root, err := db.Upsert(nil, []Update{
+		{Type: UpdatePut, Key: a, Value: []byte("a")},
+		{Type: UpdatePut, Key: b, Value: []byte("b")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	db.EnableKeyFrequency(4)
+
+	// Fill the window with reads of a, then push them out with reads of b
+	for i := 0; i < 4; i++ {
+		db.FindFromRoot(root, a, 1)
+	}
+	for i := 0; i < 4; i++ {
+		db.FindFromRoot(root, b, 1)
+	}
+
+	top := db.TopKKeys(2)
+	if len(top) != 1 {
+		t.Fatalf("Expected only one key left in window, got %d", len(top))
+	}
+	if !bytes.Equal(top[0].Key, b) {
+		t.Errorf("Window should only contain key b, got %x", top[0].Key)
+	}
+}
+
+// TestRollingStats tests windowed counters and argument validation.
+func TestRollingStats(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := db.FindFromRoot(root, makeKey32(0x01), 1); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	stats, err := db.RollingStats(10 * time.Second)
+	if err != nil {
+		t.Fatalf("RollingStats failed: %v", err)
+	}
+	if stats.WriteOps != 1 || stats.ReadOps != 1 {
+		t.Errorf("Window should cover the recent ops, got %+v", stats)
+	}
+	if stats.BytesWritten == 0 {
+		t.Error("BytesWritten should count the recent write")
+	}
+
+	if _, err := db.RollingStats(0); err != ErrInvalidArgument {
+		t.Errorf("Zero window should fail, got %v", err)
+	}
+	if _, err := db.RollingStats(time.Hour); err != ErrInvalidArgument {
+		t.Errorf("Oversized window should fail, got %v", err)
+	}
+}
+
+// TestQuiesceStats tests the quiet-database snapshot and timeout paths.
+func TestQuiesceStats(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	stats, err := db.QuiesceStats(context.Background())
+	if err != nil {
+		t.Fatalf("QuiesceStats failed: %v", err)
+	}
+	if stats.WriteOps != 1 {
+		t.Errorf("Snapshot should include the completed write, got %+v", stats)
+	}
+
+	// A pending async write keeps the database busy until the deadline
+	db.addWriteLag(1, 10)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := db.QuiesceStats(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded while writes are pending, got %v", err)
+	}
+	db.addWriteLag(-1, -10)
+}
@@ -0,0 +1,208 @@
+package monaddb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// subtreeMagic identifies a serialized subtree chunk.
+var subtreeMagic = [4]byte{'N', 'S', 'U', 'B'}
+
+// subtreeHeaderLen is the fixed part of the chunk layout: magic, prefix
+// length, subtree root hash and entry count (the prefix itself is
+// variable-length).
+const subtreeHeaderLen = 4 + 2 + 32 + 4
+
+// subtreeRootOf rebuilds a chunk's entries in a scratch in-memory trie and
+// returns its root hash, the Merkle commitment embedded in the chunk.
+// Both sides of a transfer compute it the same way, so the hash binds the
+// exact entry set rather than the bytes that happened to carry it.
+func subtreeRootOf(entries []Update) ([32]byte, error) {
+	var hash [32]byte
+	if len(entries) == 0 {
+		return hash, nil
+	}
+	scratch, err := OpenMemory()
+	if err != nil {
+		return hash, err
+	}
+	defer scratch.Close()
+	root, err := scratch.Upsert(nil, entries, 0)
+	if err != nil {
+		return hash, err
+	}
+	return root.Hash()
+}
+
+// ExportSubtree serializes every entry under prefix into a self-describing
+// chunk, stopping before the output would exceed maxBytes (0 = unlimited).
+// The chunk embeds the Merkle root of a trie holding exactly the exported
+// entries (keys stripped of the prefix, matching ExtractSubtree) plus a
+// keccak256 checksum over the payload. ImportSubtree rebuilds the entries
+// and compares against the embedded root before applying anything, and a
+// receiver can check SubtreeRoot against a root obtained from a trusted
+// source first — so a peer cannot forge entries under a root the receiver
+// trusts, which is what makes snap-sync-style state transfer safe.
+//
+// When the size limit cuts the export short, the chunk contains a valid
+// prefix of the range in key order and the embedded root commits to that
+// subset; the caller can resume from the last key it received.
+func (db *DB) ExportSubtree(root *Node, prefix []byte, maxBytes uint64, version uint64) ([]byte, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	var payload bytes.Buffer
+	payload.Write(subtreeMagic[:])
+	binary.Write(&payload, binary.BigEndian, uint16(len(prefix)))
+	payload.Write(prefix)
+
+	rootAt := payload.Len()
+	payload.Write(make([]byte, 32)) // subtree root hash, patched below
+
+	countAt := payload.Len()
+	binary.Write(&payload, binary.BigEndian, uint32(0)) // entry count, patched below
+
+	var stripped []Update
+	err := db.forEach(root, prefix, version, func(kv kvEntry) (bool, error) {
+		need := 8 + len(kv.Key) + len(kv.Value)
+		if maxBytes > 0 && uint64(payload.Len()+need)+32 > maxBytes {
+			return false, nil
+		}
+		binary.Write(&payload, binary.BigEndian, uint32(len(kv.Key)))
+		payload.Write(kv.Key)
+		binary.Write(&payload, binary.BigEndian, uint32(len(kv.Value)))
+		payload.Write(kv.Value)
+		stripped = append(stripped, Update{
+			Type:  UpdatePut,
+			Key:   append([]byte(nil), kv.Key[len(prefix):]...),
+			Value: append([]byte(nil), kv.Value...),
+		})
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subRoot, err := subtreeRootOf(stripped)
+	if err != nil {
+		return nil, err
+	}
+
+	data := payload.Bytes()
+	copy(data[rootAt:], subRoot[:])
+	binary.BigEndian.PutUint32(data[countAt:], uint32(len(stripped)))
+
+	sum := db.KeyHash(data)
+	return append(data, sum[:]...), nil
+}
+
+// SubtreeRoot returns the Merkle root embedded in a chunk produced by
+// ExportSubtree without importing it, so a syncing node can compare it
+// against a root obtained from a trusted source (such as a block header)
+// before spending any work on the entries. It parses the header only;
+// ImportSubtree performs the full verification.
+func SubtreeRoot(data []byte) ([32]byte, error) {
+	var hash [32]byte
+	if len(data) < subtreeHeaderLen+32 {
+		return hash, fmt.Errorf("monaddb: subtree chunk too short: %w", ErrInvalidArgument)
+	}
+	if !bytes.Equal(data[:4], subtreeMagic[:]) {
+		return hash, fmt.Errorf("monaddb: bad subtree magic: %w", ErrInvalidArgument)
+	}
+	prefixLen := int(binary.BigEndian.Uint16(data[4:]))
+	if 6+prefixLen+32 > len(data) {
+		return hash, fmt.Errorf("monaddb: truncated subtree header: %w", ErrInvalidArgument)
+	}
+	copy(hash[:], data[6+prefixLen:])
+	return hash, nil
+}
+
+// ImportSubtree verifies a chunk produced by ExportSubtree and inserts its
+// entries at the given version. The checksum guards against transfer
+// corruption; the entries are then rebuilt into a scratch trie and the
+// resulting root compared against the chunk's embedded Merkle root, so
+// forged or substituted entries are rejected with a *StateMismatchError
+// before any partial state lands in the trie. Callers syncing from
+// untrusted peers should additionally compare SubtreeRoot against a root
+// they trust before importing.
+func (db *DB) ImportSubtree(data []byte, version uint64) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	if len(data) < subtreeHeaderLen+32 {
+		return fmt.Errorf("monaddb: subtree chunk too short: %w", ErrInvalidArgument)
+	}
+
+	payload, sum := data[:len(data)-32], data[len(data)-32:]
+	if want := db.KeyHash(payload); !bytes.Equal(sum, want[:]) {
+		return fmt.Errorf("monaddb: subtree checksum mismatch: %w", ErrInvalidArgument)
+	}
+	if !bytes.Equal(payload[:4], subtreeMagic[:]) {
+		return fmt.Errorf("monaddb: bad subtree magic: %w", ErrInvalidArgument)
+	}
+
+	off := 4
+	prefixLen := int(binary.BigEndian.Uint16(payload[off:]))
+	off += 2
+	if off+prefixLen+32+4 > len(payload) {
+		return fmt.Errorf("monaddb: truncated subtree header: %w", ErrInvalidArgument)
+	}
+	off += prefixLen
+	var wantRoot [32]byte
+	copy(wantRoot[:], payload[off:])
+	off += 32
+	count := int(binary.BigEndian.Uint32(payload[off:]))
+	off += 4
+
+	var entries, stripped []Update
+	for i := 0; i < count; i++ {
+		if off+4 > len(payload) {
+			return fmt.Errorf("monaddb: truncated subtree entry: %w", ErrInvalidArgument)
+		}
+		keyLen := int(binary.BigEndian.Uint32(payload[off:]))
+		off += 4
+		if off+keyLen+4 > len(payload) || keyLen < prefixLen {
+			return fmt.Errorf("monaddb: truncated subtree entry: %w", ErrInvalidArgument)
+		}
+		key := payload[off : off+keyLen]
+		off += keyLen
+		valueLen := int(binary.BigEndian.Uint32(payload[off:]))
+		off += 4
+		if off+valueLen > len(payload) {
+			return fmt.Errorf("monaddb: truncated subtree entry: %w", ErrInvalidArgument)
+		}
+		value := payload[off : off+valueLen]
+		off += valueLen
+
+		entries = append(entries, Update{Type: UpdatePut, Key: key, Value: value})
+		stripped = append(stripped, Update{Type: UpdatePut, Key: key[prefixLen:], Value: value})
+	}
+
+	// Verify before committing anything: the entries must hash to the root
+	// the chunk claims to carry
+	gotRoot, err := subtreeRootOf(stripped)
+	if err != nil {
+		return err
+	}
+	if gotRoot != wantRoot {
+		return &StateMismatchError{Expected: wantRoot, Actual: gotRoot}
+	}
+
+	root, err := db.LoadRoot(version)
+	if err != nil && err != ErrNotFound && err != ErrVersionOutOfRange {
+		return err
+	}
+	for len(entries) > 0 {
+		batch := entries
+		if len(batch) > defragBatchSize {
+			batch = batch[:defragBatchSize]
+		}
+		if root, err = db.Upsert(root, batch, version); err != nil {
+			return err
+		}
+		entries = entries[len(batch):]
+	}
+	return nil
+}
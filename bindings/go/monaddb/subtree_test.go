@@ -0,0 +1,115 @@
+package monaddb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestSubtreeRoundTrip tests export, verified import, and corruption rejection.
+func TestSubtreeRoundTrip(t *testing.T) {
+	src, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer src.Close()
+
+	var root *Node
+	for i := byte(1); i <= 5; i++ {
+		root, err = src.Put(root, makeKey32(i), []byte{'v', i}, 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	data, err := src.ExportSubtree(root, nil, 0, 1)
+	if err != nil {
+		t.Fatalf("ExportSubtree failed: %v", err)
+	}
+
+	// A complete no-prefix export commits to the source trie's own root
+	rootHash, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if embedded, err := SubtreeRoot(data); err != nil || embedded != rootHash {
+		t.Errorf("Embedded root should match the source root: %x, %v", embedded, err)
+	}
+
+	dst, err := OpenDisk(t.TempDir()+"/import.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer dst.Close()
+
+	if err := dst.ImportSubtree(data, 1); err != nil {
+		t.Fatalf("ImportSubtree failed: %v", err)
+	}
+	for i := byte(1); i <= 5; i++ {
+		node, err := dst.Find(makeKey32(i), 1)
+		if err != nil {
+			t.Fatalf("Find after import failed: %v", err)
+		}
+		value, err := node.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if !bytes.Equal(value, []byte{'v', i}) {
+			t.Errorf("Imported value mismatch for key %d: got %q", i, value)
+		}
+	}
+
+	// A flipped payload byte must fail verification before anything applies
+	corrupt := append([]byte(nil), data...)
+	corrupt[10] ^= 0xFF
+	if err := dst.ImportSubtree(corrupt, 2); err == nil {
+		t.Error("Corrupted chunk should be rejected")
+	}
+
+	// A forged chunk with a recomputed checksum must still fail the Merkle
+	// root comparison: the checksum only covers transfer corruption
+	forged := append([]byte(nil), data...)
+	forged[len(forged)-40] ^= 0xFF // flip a byte in the last entry's value
+	payload := forged[:len(forged)-32]
+	sum := dst.KeyHash(payload)
+	copy(forged[len(forged)-32:], sum[:])
+	var mismatch *StateMismatchError
+	if err := dst.ImportSubtree(forged, 2); !errors.As(err, &mismatch) {
+		t.Errorf("Forged entries should fail the root check, got %v", err)
+	}
+}
+
+// TestExportSubtreeMaxBytes tests that the size limit truncates cleanly.
+func TestExportSubtreeMaxBytes(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for i := byte(1); i <= 10; i++ {
+		root, err = db.Put(root, makeKey32(i), bytes.Repeat([]byte{i}, 64), 1)
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	data, err := db.ExportSubtree(root, nil, 300, 1)
+	if err != nil {
+		t.Fatalf("ExportSubtree failed: %v", err)
+	}
+	if uint64(len(data)) > 300 {
+		t.Errorf("Chunk should respect maxBytes, got %d bytes", len(data))
+	}
+
+	// The truncated chunk still verifies and imports
+	dst, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer dst.Close()
+	if err := dst.ImportSubtree(data, 1); err != nil {
+		t.Errorf("Truncated chunk should still import: %v", err)
+	}
+}
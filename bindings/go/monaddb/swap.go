@@ -0,0 +1,61 @@
+package monaddb
+
+import "bytes"
+
+// swapRead returns the value under key, or nil when the key is absent.
+func (db *DB) swapRead(root *Node, key []byte, version uint64) ([]byte, error) {
+	node, err := db.FindFromRoot(root, key, version)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, nil
+	}
+	return node.Value()
+}
+
+// Swap2 exchanges the values of two keys, but only when both still hold
+// the values the caller expects — a double compare-and-swap for
+// multi-party contract state. An expected value of nil means "the key is
+// absent". When either comparison fails nothing is written and the
+// original root comes back with false; on success both keys are updated
+// in one Upsert, so the exchange is atomic and an absent side deletes the
+// key it swaps into.
+func (db *DB) Swap2(root *Node, keyA []byte, expectedA []byte, keyB []byte, expectedB []byte, version uint64) (*Node, bool, error) {
+	if db == nil || db.ptr == nil {
+		return nil, false, ErrNullPointer
+	}
+
+	valueA, err := db.swapRead(root, keyA, version)
+	if err != nil {
+		return nil, false, err
+	}
+	valueB, err := db.swapRead(root, keyB, version)
+	if err != nil {
+		return nil, false, err
+	}
+	if !bytes.Equal(valueA, expectedA) || !bytes.Equal(valueB, expectedB) {
+		return root, false, nil
+	}
+	if valueA == nil && valueB == nil {
+		return root, true, nil // Two absent keys swap into themselves
+	}
+
+	updates := make([]Update, 0, 2)
+	if valueB == nil {
+		updates = append(updates, Update{Type: UpdateDelete, Key: keyA})
+	} else {
+		updates = append(updates, Update{Type: UpdatePut, Key: keyA, Value: valueB})
+	}
+	if valueA == nil {
+		updates = append(updates, Update{Type: UpdateDelete, Key: keyB})
+	} else {
+		updates = append(updates, Update{Type: UpdatePut, Key: keyB, Value: valueA})
+	}
+
+	newRoot, err := db.Upsert(root, updates, version)
+	if err != nil {
+		return nil, false, err
+	}
+	return newRoot, true, nil
+}
@@ -0,0 +1,64 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestSwap2 tests the double compare-and-swap.
+func TestSwap2(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Upsert(nil, []Update{
+		{Key: makeKey32(0x0A), Value: []byte("alpha")},
+		{Key: makeKey32(0x0B), Value: []byte("beta")},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	// A stale expectation leaves the root untouched
+	same, swapped, err := db.Swap2(root, makeKey32(0x0A), []byte("stale"), makeKey32(0x0B), []byte("beta"), 2)
+	if err != nil {
+		t.Fatalf("Swap2 failed: %v", err)
+	}
+	if swapped || same != root {
+		t.Errorf("Failed condition should return the original root, got swapped=%v", swapped)
+	}
+
+	newRoot, swapped, err := db.Swap2(root, makeKey32(0x0A), []byte("alpha"), makeKey32(0x0B), []byte("beta"), 2)
+	if err != nil {
+		t.Fatalf("Swap2 failed: %v", err)
+	}
+	if !swapped {
+		t.Fatal("Matching expectations should swap")
+	}
+	for key, want := range map[byte]string{0x0A: "beta", 0x0B: "alpha"} {
+		node, err := db.FindFromRoot(newRoot, makeKey32(key), 2)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if value, err := node.Value(); err != nil || string(value) != want {
+			t.Errorf("Key %x should hold %q after the swap, got %q, %v", key, want, value, err)
+		}
+	}
+
+	// Swapping with an absent side moves the value across
+	newRoot, swapped, err = db.Swap2(newRoot, makeKey32(0x0A), []byte("beta"), makeKey32(0x0C), nil, 3)
+	if err != nil || !swapped {
+		t.Fatalf("Swap with an absent side failed: %v, swapped=%v", err, swapped)
+	}
+	if node, err := db.FindFromRoot(newRoot, makeKey32(0x0A), 3); err != nil || node != nil {
+		t.Errorf("The occupied side should now be absent, got %v, %v", node, err)
+	}
+	node, err := db.FindFromRoot(newRoot, makeKey32(0x0C), 3)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "beta" {
+		t.Errorf("The absent side should receive the value, got %q, %v", value, err)
+	}
+}
@@ -0,0 +1,280 @@
+package monaddb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Options configures a disk database beyond the basic OpenDisk parameters.
+type Options struct {
+	// Create the database if it doesn't exist
+	Create bool
+
+	// Number of historical versions to retain in the hot store (0 = default)
+	HistoryLength uint64
+
+	// TieredStorage enables the hot/warm/cold layout below. When false the
+	// remaining tiering fields are ignored.
+	TieredStorage bool
+
+	// WarmStoragePath holds versions that aged out of the hot window.
+	WarmStoragePath string
+
+	// ColdStoragePath holds archived versions that aged out of warm.
+	ColdStoragePath string
+
+	// HotVersions is the number of most recent versions kept in the
+	// primary store before they migrate to warm (0 = never migrate).
+	HotVersions int
+
+	// GCIOPriority overrides the I/O priority used by background
+	// maintenance passes (0 = idle; see SetIOPriority).
+	GCIOPriority IOPriority
+
+	// LargeValueThreshold keeps values above this many encoded bytes
+	// outside the trie: the leaf stores a 32-byte content hash and the
+	// value lives in a sidecar store next to the database, so large EVM
+	// code blobs do not inflate node sizes (0 = default 256).
+	LargeValueThreshold uint64
+
+	// WriteLogSize keeps the last N puts in an in-memory ring so
+	// GetLatestN can serve "recent writes" feeds without a secondary
+	// index (0 = disabled).
+	WriteLogSize int
+
+	// ReadMostly freezes the touch-cache order: GetAndTouch stops
+	// promoting nodes and behaves like FindFromRoot, avoiding cache
+	// churn in scan-heavy workloads.
+	ReadMostly bool
+
+	// AllowNegativeCounter lets Decrement wrap below zero instead of
+	// failing with ErrUnderflow; the stored value then reads as a
+	// two's-complement negative.
+	AllowNegativeCounter bool
+
+	// MaxAppendedValueSize caps the combined size AppendToValue may
+	// produce; larger results fail with ErrValueTooLarge (0 = no cap).
+	MaxAppendedValueSize int
+}
+
+// SizeBreakdown reports the on-disk footprint of one storage tier.
+type SizeBreakdown struct {
+	Path  string
+	Files int
+	Bytes uint64
+}
+
+// tierSet holds the secondary stores of a tiered database and tracks how
+// far migration has progressed.
+type tierSet struct {
+	warm        *DB
+	cold        *DB
+	hotVersions uint64
+
+	mu       sync.Mutex
+	migrated uint64 // highest version already moved out of the hot window
+}
+
+// OpenDiskWithOptions opens a disk-based database with the full option
+// set. With Options.TieredStorage enabled, the warm and cold stores are
+// opened alongside the primary one: writes land in the hot store, versions
+// older than Options.HotVersions are copied down a tier as new versions
+// arrive, and Find cascades hot -> warm -> cold transparently.
+//
+// Construction-time observability settings (WithLogger, WithMetrics,
+// WithTracer) are applied before any tier store opens, so the whole open
+// sequence is observed.
+func OpenDiskWithOptions(path string, opts Options, options ...Option) (*DB, error) {
+	db, err := OpenDisk(path, opts.Create, opts.HistoryLength)
+	if err != nil {
+		return nil, err
+	}
+	db.gcIOPrio = opts.GCIOPriority
+	db.external = newExternalStore(opts.LargeValueThreshold, path)
+	if opts.WriteLogSize > 0 {
+		db.writeLog.Store(newWriteRing(opts.WriteLogSize))
+	}
+	db.readMostly = opts.ReadMostly
+	db.allowNegativeCounter = opts.AllowNegativeCounter
+	db.maxAppendedValueSize = opts.MaxAppendedValueSize
+	for _, o := range options {
+		o(db)
+	}
+	if !opts.TieredStorage {
+		db.log().Info("database opened", "path", path)
+		return db, nil
+	}
+	if opts.WarmStoragePath == "" || opts.ColdStoragePath == "" {
+		db.Close()
+		return nil, ErrInvalidArgument
+	}
+
+	warm, err := OpenDisk(opts.WarmStoragePath, opts.Create, 0)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	cold, err := OpenDisk(opts.ColdStoragePath, opts.Create, 0)
+	if err != nil {
+		warm.Close()
+		db.Close()
+		return nil, err
+	}
+
+	db.tiers = &tierSet{warm: warm, cold: cold, hotVersions: uint64(opts.HotVersions)}
+	db.log().Info("database opened", "path", path, "tiered", true)
+	return db, nil
+}
+
+// OpenWithRetry opens a disk database, retrying transient failures such as
+// a file system that has not finished mounting. Only recoverable errors
+// (ErrIO) are retried, up to maxRetries additional attempts with
+// retryInterval between them; errors that cannot be fixed by waiting
+// (ErrInvalidArgument and the like) are returned immediately. The last
+// error is returned once the retries are exhausted.
+func OpenWithRetry(path string, opts Options, maxRetries int, retryInterval time.Duration) (*DB, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryInterval)
+		}
+		db, err := OpenDiskWithOptions(path, opts)
+		if err == nil {
+			return db, nil
+		}
+		if !errors.Is(err, ErrIO) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// findTiered looks up a key in the warm and cold stores after the hot
+// store came up empty.
+func (t *tierSet) find(key []byte, version uint64) (*Node, error) {
+	node, err := t.warm.Find(key, version)
+	if err == nil && node != nil {
+		return node, nil
+	}
+	if err != nil && err != ErrNotFound && err != ErrVersionOutOfRange {
+		return nil, err
+	}
+	return t.cold.Find(key, version)
+}
+
+// migrate copies versions that have left the hot window down a tier.
+// Called after a write advanced the latest version. The hot store still
+// prunes by its own history length; migration just makes sure the data
+// reaches warm (and eventually cold) before that happens.
+func (t *tierSet) migrate(db *DB, latest uint64) error {
+	if t.hotVersions == 0 || latest <= t.hotVersions {
+		return nil
+	}
+	cutoff := latest - t.hotVersions
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for v := t.migrated + 1; v <= cutoff; v++ {
+		if !db.VersionIsValid(v) {
+			continue
+		}
+		db.log().Debug("migrating version to warm tier", "version", v)
+		if err := copyVersion(db, t.warm, v); err != nil {
+			return err
+		}
+		// Warm versions older than a further hot window archive to cold
+		if v > t.hotVersions {
+			if err := copyVersion(t.warm, t.cold, v-t.hotVersions); err != nil {
+				return err
+			}
+		}
+	}
+	t.migrated = cutoff
+	return nil
+}
+
+// copyVersion replays every key-value pair of one version from src into dst.
+func copyVersion(src, dst *DB, version uint64) error {
+	root, err := src.LoadRoot(version)
+	if err != nil || root == nil {
+		return err
+	}
+
+	dstRoot, err := dst.LoadRoot(version)
+	if err != nil && err != ErrVersionOutOfRange {
+		return err
+	}
+
+	batch := make([]Update, 0, defragBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		r, err := dst.Upsert(dstRoot, batch, version)
+		if err != nil {
+			return err
+		}
+		dstRoot = r
+		batch = batch[:0]
+		return nil
+	}
+
+	err = src.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		batch = append(batch, Update{Type: UpdatePut, Key: kv.Key, Value: kv.Value})
+		if len(batch) < defragBatchSize {
+			return true, nil
+		}
+		return true, flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// TierStats reports the on-disk footprint of each storage tier. For a
+// database opened without tiering, warm and cold are zero.
+func (db *DB) TierStats() (hot, warm, cold SizeBreakdown, err error) {
+	if db == nil || db.ptr == nil {
+		return hot, warm, cold, ErrNullPointer
+	}
+	if db.path != "" {
+		if hot, err = measurePath(db.path); err != nil {
+			return hot, warm, cold, err
+		}
+	}
+	if db.tiers != nil {
+		if warm, err = measurePath(db.tiers.warm.path); err != nil {
+			return hot, warm, cold, err
+		}
+		if cold, err = measurePath(db.tiers.cold.path); err != nil {
+			return hot, warm, cold, err
+		}
+	}
+	return hot, warm, cold, nil
+}
+
+// measurePath sums file sizes under a database path (a single file or a
+// directory tree).
+func measurePath(path string) (SizeBreakdown, error) {
+	b := SizeBreakdown{Path: path}
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			b.Files++
+			b.Bytes += uint64(info.Size())
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	return b, err
+}
@@ -0,0 +1,113 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestTieredStorage tests migration across tiers and cascading reads.
+func TestTieredStorage(t *testing.T) {
+	dir := t.TempDir()
+	db, err := OpenDiskWithOptions(dir+"/hot.db", Options{
+		Create:          true,
+		TieredStorage:   true,
+		WarmStoragePath: dir + "/warm.db",
+		ColdStoragePath: dir + "/cold.db",
+		HotVersions:     2,
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	key := makeKey32(0x01)
+	var root *Node
+	for v := uint64(1); v <= 5; v++ {
+		root, err = db.Put(root, key, []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put at version %d failed: %v", v, err)
+		}
+	}
+
+	// Versions 1-3 aged out of the hot window (latest 5, HotVersions 2)
+	// and must be readable through the cascade
+	for v := uint64(1); v <= 5; v++ {
+		node, err := db.Find(key, v)
+		if err != nil {
+			t.Fatalf("Find at version %d failed: %v", v, err)
+		}
+		if node == nil {
+			t.Fatalf("Key missing at version %d", v)
+		}
+		value, err := node.Value()
+		if err != nil {
+			t.Fatalf("Value at version %d failed: %v", v, err)
+		}
+		if !bytes.Equal(value, []byte{byte(v)}) {
+			t.Errorf("Version %d value mismatch: got %x", v, value)
+		}
+	}
+
+	// Migrated versions exist in the warm store directly
+	if node, err := db.tiers.warm.Find(key, 1); err != nil || node == nil {
+		t.Errorf("Version 1 should have migrated to warm (node=%v, err=%v)", node, err)
+	}
+
+	hot, warm, cold, err := db.TierStats()
+	if err != nil {
+		t.Fatalf("TierStats failed: %v", err)
+	}
+	if hot.Bytes == 0 || warm.Bytes == 0 {
+		t.Errorf("Hot and warm tiers should be non-empty: hot=%d warm=%d", hot.Bytes, warm.Bytes)
+	}
+	if cold.Path == "" {
+		t.Error("Cold tier path should be reported")
+	}
+}
+
+// TestTierStatsUntiered tests that a plain disk database reports only hot.
+func TestTierStatsUntiered(t *testing.T) {
+	db, err := OpenDisk(t.TempDir()+"/plain.db", true, 10)
+	if err != nil {
+		t.Fatalf("OpenDisk failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	hot, warm, cold, err := db.TierStats()
+	if err != nil {
+		t.Fatalf("TierStats failed: %v", err)
+	}
+	if hot.Bytes == 0 {
+		t.Error("Hot tier should be non-empty")
+	}
+	if warm.Bytes != 0 || cold.Bytes != 0 {
+		t.Errorf("Warm and cold should be zero without tiering: warm=%d cold=%d", warm.Bytes, cold.Bytes)
+	}
+}
+
+// TestOpenWithRetry tests that non-recoverable errors fail immediately.
+func TestOpenWithRetry(t *testing.T) {
+	path := t.TempDir() + "/retry.db"
+
+	db, err := OpenWithRetry(path, Options{Create: true, HistoryLength: 10}, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("OpenWithRetry failed: %v", err)
+	}
+	db.Close()
+
+	// A tiered config missing its storage paths is invalid and must not
+	// burn retries waiting for it to become valid
+	start := time.Now()
+	_, err = OpenWithRetry(path, Options{TieredStorage: true}, 3, 100*time.Millisecond)
+	if err != ErrInvalidArgument {
+		t.Errorf("Expected ErrInvalidArgument, got %v", err)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Error("Non-recoverable errors should not be retried")
+	}
+}
@@ -0,0 +1,66 @@
+package monaddb
+
+import (
+	"container/list"
+	"sync"
+)
+
+// touchCacheSize bounds how many node handles GetAndTouch keeps live.
+const touchCacheSize = 1024
+
+// touchCache is an LRU of node handles. Holding a handle pins the node's
+// shared reference in memory, so the engine cannot drop a touched node
+// until it falls off the back of the cache — the handle itself is the
+// residency guarantee.
+type touchCache struct {
+	mu    sync.Mutex
+	order *list.List // front = most recently touched
+	byKey map[string]*list.Element
+}
+
+// touchEntry is one cache slot: the key and the pinned handle.
+type touchEntry struct {
+	key  string
+	node *Node
+}
+
+// touch promotes key to the front, inserting it if absent and evicting
+// the least recently touched entry beyond the size bound.
+func (c *touchCache) touch(key []byte, node *Node) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.order == nil {
+		c.order = list.New()
+		c.byKey = make(map[string]*list.Element)
+	}
+	k := string(key)
+	if elem, ok := c.byKey[k]; ok {
+		elem.Value.(*touchEntry).node = node
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.byKey[k] = c.order.PushFront(&touchEntry{key: k, node: node})
+	if c.order.Len() > touchCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.byKey, oldest.Value.(*touchEntry).key)
+	}
+}
+
+// GetAndTouch looks a key up like FindFromRoot and additionally promotes
+// the found node to the front of the touch cache, refreshing its eviction
+// eligibility: the cache holds the node handle live, so a touched node
+// stays resident until 1024 fresher touches push it out. Under
+// Options.ReadMostly the cache order is frozen and GetAndTouch degrades
+// to plain FindFromRoot semantics.
+func (db *DB) GetAndTouch(root *Node, key []byte, version uint64) (*Node, error) {
+	node, err := db.FindFromRoot(root, key, version)
+	if err != nil {
+		return nil, err
+	}
+	if node != nil && !db.readMostly {
+		db.touched.touch(key, node)
+	}
+	return node, nil
+}
@@ -0,0 +1,67 @@
+package monaddb
+
+import (
+	"testing"
+)
+
+// TestGetAndTouch tests lookup semantics and cache promotion.
+func TestGetAndTouch(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.GetAndTouch(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("GetAndTouch failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "value" {
+		t.Errorf("Expected FindFromRoot semantics, got %q, %v", value, err)
+	}
+	if missing, err := db.GetAndTouch(root, makeKey32(0x02), 1); err != nil || missing != nil {
+		t.Errorf("Missing keys should return a nil node, got %v, %v", missing, err)
+	}
+
+	// The touched node is held at the front of the cache
+	if db.touched.order == nil || db.touched.order.Len() != 1 {
+		t.Fatal("Touched node should be cached")
+	}
+	front := db.touched.order.Front().Value.(*touchEntry)
+	if front.key != string(makeKey32(0x01)) {
+		t.Errorf("Touched key should be at the front, got %x", front.key)
+	}
+}
+
+// TestGetAndTouchReadMostly tests the frozen-cache fallback.
+func TestGetAndTouchReadMostly(t *testing.T) {
+	db, err := OpenDiskWithOptions(t.TempDir()+"/readmostly.db", Options{
+		Create:     true,
+		ReadMostly: true,
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	root, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.GetAndTouch(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("GetAndTouch failed: %v", err)
+	}
+	if value, err := node.Value(); err != nil || string(value) != "value" {
+		t.Errorf("Fallback should keep lookup semantics, got %q, %v", value, err)
+	}
+	if db.touched.order != nil {
+		t.Error("ReadMostly mode should not populate the touch cache")
+	}
+}
@@ -0,0 +1,198 @@
+package monaddb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Trace record opcodes. Each record is the opcode followed by its
+// big-endian fields; see recordUpsert and recordFind for the layouts.
+const (
+	traceOpUpsert byte = 1
+	traceOpFind   byte = 2
+)
+
+// traceStatus summarizes an operation's result in one byte.
+const (
+	traceStatusOK       byte = 0
+	traceStatusNotFound byte = 1
+	traceStatusError    byte = 2
+)
+
+// traceRecorder serializes operation records to the trace writer. Records
+// are buffered per operation and written in one call under the mutex, so
+// concurrent operations never interleave bytes.
+type traceRecorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (tr *traceRecorder) recordUpsert(updates []Update, version uint64, status byte) {
+	var buf bytes.Buffer
+	buf.WriteByte(traceOpUpsert)
+	buf.WriteByte(status)
+	binary.Write(&buf, binary.BigEndian, version)
+	binary.Write(&buf, binary.BigEndian, uint32(len(updates)))
+	for _, u := range updates {
+		buf.WriteByte(byte(u.Type))
+		binary.Write(&buf, binary.BigEndian, uint32(len(u.Key)))
+		buf.Write(u.Key)
+		binary.Write(&buf, binary.BigEndian, uint32(len(u.Value)))
+		buf.Write(u.Value)
+	}
+
+	tr.mu.Lock()
+	tr.w.Write(buf.Bytes())
+	tr.mu.Unlock()
+}
+
+func (tr *traceRecorder) recordFind(key []byte, version uint64, status byte) {
+	var buf bytes.Buffer
+	buf.WriteByte(traceOpFind)
+	buf.WriteByte(status)
+	binary.Write(&buf, binary.BigEndian, version)
+	binary.Write(&buf, binary.BigEndian, uint32(len(key)))
+	buf.Write(key)
+
+	tr.mu.Lock()
+	tr.w.Write(buf.Bytes())
+	tr.mu.Unlock()
+}
+
+// StartTrace begins recording every subsequent operation — its arguments
+// and result status — as binary records to w. A production workload traced
+// this way becomes a deterministic test case: feed the bytes to
+// ReplayTrace to re-execute it. Returns ErrInvalidArgument if a trace is
+// already running.
+func (db *DB) StartTrace(w io.Writer) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	if w == nil {
+		return ErrInvalidArgument
+	}
+	if !db.trace.CompareAndSwap(nil, &traceRecorder{w: w}) {
+		return ErrInvalidArgument
+	}
+	return nil
+}
+
+// StopTrace stops recording. Returns ErrInvalidArgument if no trace is
+// running.
+func (db *DB) StopTrace() error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+	if db.trace.Swap(nil) == nil {
+		return ErrInvalidArgument
+	}
+	return nil
+}
+
+// ReplayTrace re-executes a recorded trace against db, which should start
+// empty for a faithful reproduction. Upserts are chained through the roots
+// the replay itself produces; finds run against the latest replayed root,
+// with a not-found result treated as normal (the original may have probed
+// absent keys too). Returns ErrInvalidArgument on a malformed trace.
+func ReplayTrace(db *DB, r io.Reader) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+
+	var root *Node
+	var opcode [1]byte
+	for {
+		if _, err := io.ReadFull(r, opcode[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("monaddb: truncated trace: %w", ErrInvalidArgument)
+		}
+
+		var header [9]byte // status + version
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return fmt.Errorf("monaddb: truncated trace record: %w", ErrInvalidArgument)
+		}
+		version := binary.BigEndian.Uint64(header[1:])
+
+		switch opcode[0] {
+		case traceOpUpsert:
+			count, err := readTraceU32(r)
+			if err != nil {
+				return err
+			}
+			updates := make([]Update, count)
+			for i := range updates {
+				var entryType [1]byte
+				if _, err := io.ReadFull(r, entryType[:]); err != nil {
+					return fmt.Errorf("monaddb: truncated trace update: %w", ErrInvalidArgument)
+				}
+				key, err := readTraceBytes(r)
+				if err != nil {
+					return err
+				}
+				value, err := readTraceBytes(r)
+				if err != nil {
+					return err
+				}
+				updates[i] = Update{Type: UpdateType(entryType[0]), Key: key, Value: value}
+			}
+			newRoot, err := db.Upsert(root, updates, version)
+			if err != nil {
+				return err
+			}
+			root = newRoot
+		case traceOpFind:
+			key, err := readTraceBytes(r)
+			if err != nil {
+				return err
+			}
+			if root != nil {
+				if _, err := db.FindFromRoot(root, key, version); err != nil && !errors.Is(err, ErrNotFound) {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("monaddb: unknown trace opcode %d: %w", opcode[0], ErrInvalidArgument)
+		}
+	}
+}
+
+func readTraceU32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, fmt.Errorf("monaddb: truncated trace field: %w", ErrInvalidArgument)
+	}
+	return binary.BigEndian.Uint32(buf[:]), nil
+}
+
+func readTraceBytes(r io.Reader) ([]byte, error) {
+	n, err := readTraceU32(r)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("monaddb: truncated trace field: %w", ErrInvalidArgument)
+	}
+	return buf, nil
+}
+
+// traceErrStatus maps an operation error to its trace status byte.
+func traceErrStatus(err error) byte {
+	switch {
+	case err == nil:
+		return traceStatusOK
+	case errors.Is(err, ErrNotFound):
+		return traceStatusNotFound
+	default:
+		return traceStatusError
+	}
+}
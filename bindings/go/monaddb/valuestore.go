@@ -0,0 +1,195 @@
+package monaddb
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultLargeValueThreshold is the externalization cutoff applied when
+// Options.LargeValueThreshold is left zero.
+const defaultLargeValueThreshold = 256
+
+// externalStore keeps values that exceed the large-value threshold outside
+// the trie. Leaves then carry only a 32-byte content hash, so a multi-
+// kilobyte contract blob no longer inflates node sizes along its path.
+// Values are kept in memory and, for disk databases, mirrored to one file
+// per hash in a sidecar directory next to the database.
+type externalStore struct {
+	threshold uint64
+	dir       string // sidecar directory; empty for in-memory databases
+
+	mu     sync.Mutex
+	values map[[32]byte][]byte
+	refs   map[[32]byte]uint64
+}
+
+// externalStorePath returns the sidecar directory holding external values.
+func externalStorePath(path string) string {
+	return path + ".values"
+}
+
+func newExternalStore(threshold uint64, path string) *externalStore {
+	if threshold == 0 {
+		threshold = defaultLargeValueThreshold
+	}
+	dir := ""
+	if path != "" {
+		dir = externalStorePath(path)
+	}
+	return &externalStore{
+		threshold: threshold,
+		dir:       dir,
+		values:    make(map[[32]byte][]byte),
+		refs:      make(map[[32]byte]uint64),
+	}
+}
+
+// put stores value under its content hash and returns the hash. Storing
+// the same bytes twice shares the single entry and bumps its reference
+// count, which is what makes the store deduplicating.
+func (es *externalStore) put(db *DB, value []byte) ([32]byte, error) {
+	hash := db.KeyHash(value)
+
+	es.mu.Lock()
+	_, seen := es.values[hash]
+	if !seen {
+		es.values[hash] = append([]byte(nil), value...)
+	}
+	es.refs[hash]++
+	es.mu.Unlock()
+
+	if seen || es.dir == "" {
+		return hash, nil
+	}
+	if err := os.MkdirAll(es.dir, 0o755); err != nil {
+		return hash, err
+	}
+	if err := os.WriteFile(es.valuePath(hash), value, 0o644); err != nil {
+		return hash, err
+	}
+	return hash, nil
+}
+
+// get retrieves a value by content hash, falling back to the sidecar
+// directory for values written by an earlier process.
+func (es *externalStore) get(hash [32]byte) ([]byte, error) {
+	es.mu.Lock()
+	value, ok := es.values[hash]
+	es.mu.Unlock()
+	if ok {
+		return append([]byte(nil), value...), nil
+	}
+
+	if es.dir == "" {
+		return nil, ErrNotFound
+	}
+	value, err := os.ReadFile(es.valuePath(hash))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	es.mu.Lock()
+	es.values[hash] = value
+	if es.refs[hash] == 0 {
+		// A value recovered from the sidecar is reachable from at
+		// least one persisted leaf
+		es.refs[hash] = 1
+	}
+	es.mu.Unlock()
+	return append([]byte(nil), value...), nil
+}
+
+// release drops one reference to a stored value, removing it (and its
+// sidecar file) once the count reaches zero.
+func (es *externalStore) release(hash [32]byte) error {
+	es.mu.Lock()
+	count, ok := es.refs[hash]
+	if !ok {
+		es.mu.Unlock()
+		return ErrNotFound
+	}
+	if count > 1 {
+		es.refs[hash] = count - 1
+		es.mu.Unlock()
+		return nil
+	}
+	delete(es.refs, hash)
+	delete(es.values, hash)
+	es.mu.Unlock()
+
+	if es.dir == "" {
+		return nil
+	}
+	if err := os.Remove(es.valuePath(hash)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (es *externalStore) valuePath(hash [32]byte) string {
+	return filepath.Join(es.dir, hex.EncodeToString(hash[:]))
+}
+
+// ContentAddress stores value in the external store and returns its
+// content hash, taking one reference on the caller's behalf. Storing the
+// same bytes from two places — say two accounts deploying identical code —
+// shares a single entry. Requires a database opened with
+// Options.LargeValueThreshold; otherwise ErrNotSupported.
+func (db *DB) ContentAddress(value []byte) ([32]byte, error) {
+	es := db.external
+	if es == nil {
+		return [32]byte{}, ErrNotSupported
+	}
+	return es.put(db, value)
+}
+
+// GetByContentHash retrieves a value previously stored under its content
+// hash, either through ContentAddress or by a write that crossed the
+// large-value threshold. Returns ErrNotFound for an unknown hash.
+func (db *DB) GetByContentHash(hash [32]byte) ([]byte, error) {
+	es := db.external
+	if es == nil {
+		return nil, ErrNotSupported
+	}
+	return es.get(hash)
+}
+
+// ReleaseContentHash drops one reference to a content-addressed value.
+// The value is garbage collected — removed from memory and from the
+// sidecar directory — only when every reference has been released, so a
+// blob still reachable from another leaf survives.
+func (db *DB) ReleaseContentHash(hash [32]byte) error {
+	es := db.external
+	if es == nil {
+		return ErrNotSupported
+	}
+	return es.release(hash)
+}
+
+// externalizeUpdates replaces Put values larger than the threshold with an
+// indirect frame carrying the content hash of the stored bytes. It runs
+// after compression, so the threshold applies to the encoded size; values
+// that compress below it stay inline.
+func (db *DB) externalizeUpdates(updates []Update, es *externalStore) ([]Update, error) {
+	out := make([]Update, len(updates))
+	for i, u := range updates {
+		out[i] = u
+		if u.Type != UpdatePut || uint64(len(u.Value)) <= es.threshold {
+			continue
+		}
+		hash, err := es.put(db, u.Value)
+		if err != nil {
+			return nil, err
+		}
+		frame := make([]byte, valueHeaderLen+len(hash))
+		copy(frame, valueMagic[:])
+		frame[len(valueMagic)] = codecIndirect
+		copy(frame[valueHeaderLen:], hash[:])
+		out[i].Value = frame
+	}
+	return out, nil
+}
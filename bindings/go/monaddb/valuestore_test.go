@@ -0,0 +1,147 @@
+package monaddb
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestLargeValueExternalized tests that values above the threshold round-trip
+// through the external store.
+func TestLargeValueExternalized(t *testing.T) {
+	path := t.TempDir() + "/large.db"
+	db, err := OpenDiskWithOptions(path, Options{Create: true, LargeValueThreshold: 64})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	large := bytes.Repeat([]byte{0xAB}, 500)
+	root, err := db.Put(nil, makeKey32(0x01), large, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, makeKey32(0x01), 1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, large) {
+		t.Errorf("External value should round-trip, got %d bytes", len(value))
+	}
+
+	entries, err := os.ReadDir(externalStorePath(path))
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 external value file, got %d", len(entries))
+	}
+}
+
+// TestSmallValueStaysInline tests that values at or below the threshold do
+// not touch the external store.
+func TestSmallValueStaysInline(t *testing.T) {
+	path := t.TempDir() + "/small.db"
+	db, err := OpenDiskWithOptions(path, Options{Create: true, LargeValueThreshold: 64})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	small := bytes.Repeat([]byte{0xCD}, 32)
+	root, err := db.Put(nil, makeKey32(0x02), small, 1)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, makeKey32(0x02), 1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, small) {
+		t.Errorf("Inline value should round-trip")
+	}
+
+	if _, err := os.Stat(externalStorePath(path)); !os.IsNotExist(err) {
+		t.Errorf("Sidecar directory should not exist for inline values, stat err: %v", err)
+	}
+}
+
+// TestContentAddressDedup tests that identical values share one entry and
+// survive until every reference is released.
+func TestContentAddressDedup(t *testing.T) {
+	path := t.TempDir() + "/dedup.db"
+	db, err := OpenDiskWithOptions(path, Options{Create: true, LargeValueThreshold: 64})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	code := bytes.Repeat([]byte{0x60}, 300)
+	hash1, err := db.ContentAddress(code)
+	if err != nil {
+		t.Fatalf("ContentAddress failed: %v", err)
+	}
+	hash2, err := db.ContentAddress(code)
+	if err != nil {
+		t.Fatalf("ContentAddress failed: %v", err)
+	}
+	if hash1 != hash2 {
+		t.Error("Identical values should share one content hash")
+	}
+
+	entries, err := os.ReadDir(externalStorePath(path))
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 shared value file, got %d", len(entries))
+	}
+
+	got, err := db.GetByContentHash(hash1)
+	if err != nil {
+		t.Fatalf("GetByContentHash failed: %v", err)
+	}
+	if !bytes.Equal(got, code) {
+		t.Error("GetByContentHash should return the stored value")
+	}
+
+	// First release leaves the second reference intact
+	if err := db.ReleaseContentHash(hash1); err != nil {
+		t.Fatalf("ReleaseContentHash failed: %v", err)
+	}
+	if _, err := db.GetByContentHash(hash1); err != nil {
+		t.Errorf("Value should survive while referenced, got %v", err)
+	}
+
+	// Second release collects it
+	if err := db.ReleaseContentHash(hash1); err != nil {
+		t.Fatalf("ReleaseContentHash failed: %v", err)
+	}
+	if _, err := db.GetByContentHash(hash1); err != ErrNotFound {
+		t.Errorf("Released value should be gone, got %v", err)
+	}
+}
+
+// TestGetByContentHashUnknown tests the unknown-hash path.
+func TestGetByContentHashUnknown(t *testing.T) {
+	path := t.TempDir() + "/unknown.db"
+	db, err := OpenDiskWithOptions(path, Options{Create: true})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.GetByContentHash([32]byte{0x01}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package monaddb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// VerifyAll checks the whole database for consistency — the fsck of
+// monaddb. For every retained version it recomputes the root hash and
+// compares it against the hash the C layer reports, then walks every
+// entry, re-resolves its key through the trie's internal pointers and
+// decodes the stored value. The work runs in its own goroutine; progress
+// (0..100, by versions completed) is reported through the callback, which
+// may be nil. Cancelling the context returns ctx.Err() once the current
+// version finishes. Any inconsistency is returned as an *IntegrityError
+// naming the version and key where the damage surfaced.
+func (db *DB) VerifyAll(ctx context.Context, progress func(pct float64)) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+
+	earliest, latest := db.EarliestVersion(), db.LatestVersion()
+	if latest < earliest {
+		return nil // Nothing committed yet
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- db.verifyVersions(ctx, earliest, latest, progress)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		<-done // Let the worker finish its current version
+		return ctx.Err()
+	}
+}
+
+// verifyVersions runs the per-version checks for VerifyAll.
+func (db *DB) verifyVersions(ctx context.Context, earliest, latest uint64, progress func(pct float64)) error {
+	total := latest - earliest + 1
+	for v := earliest; v <= latest; v++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := db.verifyVersion(v); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(float64(v-earliest+1) / float64(total) * 100)
+		}
+	}
+	return nil
+}
+
+// verifyVersion checks one version: root hash, then every leaf.
+func (db *DB) verifyVersion(version uint64) error {
+	root, err := db.LoadRoot(version)
+	if err != nil {
+		if err == ErrNotFound || err == ErrVersionOutOfRange {
+			return nil // Version aged out between listing and loading
+		}
+		return &IntegrityError{Version: version, Detail: fmt.Sprintf("loading root: %v", err)}
+	}
+	if root == nil {
+		return nil
+	}
+
+	computed, err := root.Hash()
+	if err != nil {
+		return &IntegrityError{Version: version, Detail: fmt.Sprintf("hashing root: %v", err)}
+	}
+	stored, err := db.HistoricalRootHash(version)
+	if err != nil {
+		return &IntegrityError{Version: version, Detail: fmt.Sprintf("reading stored root hash: %v", err)}
+	}
+	if computed != stored {
+		return &IntegrityError{Version: version,
+			Detail: fmt.Sprintf("root hash mismatch: computed %x, stored %x", computed, stored)}
+	}
+
+	return db.forEach(root, nil, version, func(kv kvEntry) (bool, error) {
+		// Re-resolve the key through the trie's internal pointers; a
+		// descent that disagrees with the iterator means a broken link.
+		node, err := db.FindFromRoot(root, kv.Key, version)
+		if err != nil {
+			return false, &IntegrityError{Version: version, Key: append([]byte(nil), kv.Key...),
+				Detail: fmt.Sprintf("leaf unreachable by lookup: %v", err)}
+		}
+		if node == nil {
+			return false, &IntegrityError{Version: version, Key: append([]byte(nil), kv.Key...),
+				Detail: "leaf enumerated by iteration but absent by lookup"}
+		}
+		value, err := node.Value()
+		if err != nil {
+			return false, &IntegrityError{Version: version, Key: append([]byte(nil), kv.Key...),
+				Detail: fmt.Sprintf("value undecodable: %v", err)}
+		}
+		if !bytes.Equal(value, kv.Value) {
+			return false, &IntegrityError{Version: version, Key: append([]byte(nil), kv.Key...),
+				Detail: "iterator and lookup disagree on value"}
+		}
+		return true, nil
+	})
+}
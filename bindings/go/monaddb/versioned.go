@@ -0,0 +1,107 @@
+package monaddb
+
+// VersionedDB is a lightweight view of a DB with the version parameter
+// baked in. It holds no C-layer state of its own, so creating one is
+// allocation-cheap and many views over the same DB may coexist.
+type VersionedDB struct {
+	db      *DB
+	version uint64
+}
+
+// WithVersion returns a view of the database pinned to the given version.
+// All operations on the returned VersionedDB use this version implicitly.
+func (db *DB) WithVersion(version uint64) *VersionedDB {
+	return &VersionedDB{db: db, version: version}
+}
+
+// Version returns the version this view is pinned to.
+func (v *VersionedDB) Version() uint64 {
+	return v.version
+}
+
+// Find looks up a key at the pinned version. See DB.Find for the
+// disk-mode restriction.
+func (v *VersionedDB) Find(key []byte) (*Node, error) {
+	return v.db.Find(key, v.version)
+}
+
+// Put inserts a single key-value pair at the pinned version.
+func (v *VersionedDB) Put(root *Node, key, value []byte) (*Node, error) {
+	return v.db.Put(root, key, value, v.version)
+}
+
+// Delete removes a single key at the pinned version.
+func (v *VersionedDB) Delete(root *Node, key []byte) (*Node, error) {
+	return v.db.Delete(root, key, v.version)
+}
+
+// Upsert applies a batch of updates at the pinned version.
+func (v *VersionedDB) Upsert(root *Node, updates []Update) (*Node, error) {
+	return v.db.Upsert(root, updates, v.version)
+}
+
+// AtRoot returns a view pinned to both this view's version and the given
+// root, so block-processing code can thread neither through each call.
+func (v *VersionedDB) AtRoot(root *Node) *RootedDB {
+	return &RootedDB{db: v.db, root: root, version: v.version}
+}
+
+// RootedDB is a view of a DB pinned to a root node and a version.
+// Mutating methods replace the stored root with the new one, so a
+// sequence of Put/Delete/Upsert calls reads like ordinary imperative
+// code and Root returns the final root when done.
+//
+// A RootedDB is not safe for concurrent use: the stored root is replaced
+// without synchronization.
+type RootedDB struct {
+	db      *DB
+	root    *Node
+	version uint64
+}
+
+// AtRoot returns a view pinned to the given root at the database's latest
+// version. Use WithVersion(...).AtRoot(...) to pin a different version.
+func (db *DB) AtRoot(root *Node) *RootedDB {
+	return &RootedDB{db: db, root: root, version: db.LatestVersion()}
+}
+
+// Root returns the current root, reflecting all mutations made through
+// this view.
+func (r *RootedDB) Root() *Node {
+	return r.root
+}
+
+// Find looks up a key under the current root.
+func (r *RootedDB) Find(key []byte) (*Node, error) {
+	return r.db.FindFromRoot(r.root, key, r.version)
+}
+
+// Put inserts a single key-value pair and advances the stored root.
+func (r *RootedDB) Put(key, value []byte) (*Node, error) {
+	root, err := r.db.Put(r.root, key, value, r.version)
+	if err != nil {
+		return nil, err
+	}
+	r.root = root
+	return root, nil
+}
+
+// Delete removes a single key and advances the stored root.
+func (r *RootedDB) Delete(key []byte) (*Node, error) {
+	root, err := r.db.Delete(r.root, key, r.version)
+	if err != nil {
+		return nil, err
+	}
+	r.root = root
+	return root, nil
+}
+
+// Upsert applies a batch of updates and advances the stored root.
+func (r *RootedDB) Upsert(updates []Update) (*Node, error) {
+	root, err := r.db.Upsert(r.root, updates, r.version)
+	if err != nil {
+		return nil, err
+	}
+	r.root = root
+	return root, nil
+}
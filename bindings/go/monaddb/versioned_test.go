@@ -0,0 +1,114 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWithVersion tests the version-pinned database view.
+func TestWithVersion(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	v1 := db.WithVersion(1)
+	if v1.Version() != 1 {
+		t.Errorf("Version should be 1, got %d", v1.Version())
+	}
+
+	key := makeKey32(0x01)
+	root, err := v1.Put(nil, key, []byte("value"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	node, err := db.FindFromRoot(root, key, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("value")) {
+		t.Errorf("Value mismatch: got %q", value)
+	}
+
+	// Batch upsert and delete through the view
+	root, err = v1.Upsert(root, []Update{
+		{Type: UpdatePut, Key: makeKey32(0x02), Value: []byte("two")},
+	})
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	root, err = v1.Delete(root, key)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	node, err = db.FindFromRoot(root, key, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	if node != nil && node.HasValue() {
+		t.Error("Deleted key should not be found")
+	}
+}
+
+// TestAtRoot tests the root-pinned database view.
+func TestAtRoot(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	r := db.WithVersion(1).AtRoot(nil)
+
+	keyA := makeKey32(0x0A)
+	keyB := makeKey32(0x0B)
+	if _, err := r.Put(keyA, []byte("a")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := r.Put(keyB, []byte("b")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// The view threads the root through each mutation
+	node, err := r.Find(keyA)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	value, err := node.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !bytes.Equal(value, []byte("a")) {
+		t.Errorf("Value mismatch: got %q", value)
+	}
+
+	if _, err := r.Delete(keyA); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	node, err = r.Find(keyA)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if node != nil && node.HasValue() {
+		t.Error("Deleted key should not be found")
+	}
+
+	// Root reflects all mutations
+	root := r.Root()
+	if root == nil {
+		t.Fatal("Root should not be nil after mutations")
+	}
+	node, err = db.FindFromRoot(root, keyB, 1)
+	if err != nil {
+		t.Fatalf("FindFromRoot failed: %v", err)
+	}
+	if node == nil || !node.HasValue() {
+		t.Error("Key b should be present under the final root")
+	}
+}
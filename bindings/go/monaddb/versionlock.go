@@ -0,0 +1,78 @@
+package monaddb
+
+import "sync"
+
+// versionLockTable reference-counts read locks per version.
+type versionLockTable struct {
+	mu     sync.Mutex
+	counts map[uint64]int
+}
+
+// VersionLock is an opaque handle pinning one version against pruning.
+// Obtained from LockVersion; release with Unlock. The handle is freely
+// copyable; all copies refer to the same lock.
+type VersionLock struct {
+	state *versionLockState
+}
+
+type versionLockState struct {
+	db       *DB
+	version  uint64
+	released bool
+}
+
+// LockVersion pins a version so pruning skips it while the lock is held,
+// protecting long-running reads (for example a proof RPC serving that
+// version). Locks are reference-counted: any number of concurrent holders
+// may pin the same version, and it stays pinned until every holder has
+// unlocked.
+//
+// The engine's automatic history trimming runs in the C layer; the lock
+// table is consulted by the Go-level pruning integration points (such as
+// the prune callback), which must defer work on pinned versions.
+func (db *DB) LockVersion(version uint64) (VersionLock, error) {
+	if db == nil || db.ptr == nil {
+		return VersionLock{}, ErrNullPointer
+	}
+	if !db.VersionIsValid(version) {
+		return VersionLock{}, ErrVersionOutOfRange
+	}
+
+	db.versionLocks.mu.Lock()
+	if db.versionLocks.counts == nil {
+		db.versionLocks.counts = make(map[uint64]int)
+	}
+	db.versionLocks.counts[version]++
+	db.versionLocks.mu.Unlock()
+
+	return VersionLock{state: &versionLockState{db: db, version: version}}, nil
+}
+
+// Unlock releases the lock. Unlocking twice returns ErrInvalidArgument.
+func (l VersionLock) Unlock() error {
+	s := l.state
+	if s == nil || s.db == nil {
+		return ErrInvalidArgument
+	}
+
+	t := &s.db.versionLocks
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if s.released {
+		return ErrInvalidArgument
+	}
+	s.released = true
+	if n := t.counts[s.version]; n <= 1 {
+		delete(t.counts, s.version)
+	} else {
+		t.counts[s.version] = n - 1
+	}
+	return nil
+}
+
+// VersionLocked reports whether any lock currently pins the version.
+func (db *DB) VersionLocked(version uint64) bool {
+	db.versionLocks.mu.Lock()
+	defer db.versionLocks.mu.Unlock()
+	return db.versionLocks.counts[version] > 0
+}
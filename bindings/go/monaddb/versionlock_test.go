@@ -0,0 +1,47 @@
+package monaddb
+
+import "testing"
+
+// TestVersionLock tests reference-counted version pinning.
+func TestVersionLock(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	l1, err := db.LockVersion(1)
+	if err != nil {
+		t.Fatalf("LockVersion failed: %v", err)
+	}
+	l2, err := db.LockVersion(1)
+	if err != nil {
+		t.Fatalf("LockVersion failed: %v", err)
+	}
+	if !db.VersionLocked(1) {
+		t.Error("Version 1 should be locked")
+	}
+
+	// The version stays pinned until the last holder unlocks
+	if err := l1.Unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if !db.VersionLocked(1) {
+		t.Error("Version 1 should stay locked while one holder remains")
+	}
+	if err := l2.Unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if db.VersionLocked(1) {
+		t.Error("Version 1 should be unlocked after the last holder")
+	}
+
+	// Double unlock is rejected
+	if err := l1.Unlock(); err != ErrInvalidArgument {
+		t.Errorf("Double unlock should return ErrInvalidArgument, got %v", err)
+	}
+}
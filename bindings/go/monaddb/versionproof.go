@@ -0,0 +1,134 @@
+package monaddb
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// versionMetaStore holds the commit metadata recorded per version.
+type versionMetaStore struct {
+	mu   sync.Mutex
+	meta map[uint64]map[string]string
+}
+
+// UpsertWithMetadata commits updates like Upsert and additionally records
+// the given metadata (e.g. block producer, protocol round) against the
+// version, along with the commit time. The metadata travels with the
+// version in ExportVersionProof; it is held Go-side and is not part of
+// the trie or its hashes.
+func (db *DB) UpsertWithMetadata(root *Node, updates []Update, version uint64, metadata map[string]string) (*Node, error) {
+	newRoot, err := db.Upsert(root, updates, version)
+	if err != nil {
+		return nil, err
+	}
+
+	db.versionMeta.mu.Lock()
+	if db.versionMeta.meta == nil {
+		db.versionMeta.meta = make(map[uint64]map[string]string)
+	}
+	copied := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	db.versionMeta.meta[version] = copied
+	db.versionMeta.mu.Unlock()
+
+	db.versionTimes.mu.Lock()
+	if db.versionTimes.times == nil {
+		db.versionTimes.times = make(map[uint64]time.Time)
+	}
+	db.versionTimes.times[version] = time.Now()
+	db.versionTimes.mu.Unlock()
+
+	return newRoot, nil
+}
+
+// VersionProof binds a version number to its state root so a light client
+// can check a claimed block number against a root hash. The checksum
+// covers every other field; VerifyVersionProof validates it without
+// database access.
+type VersionProof struct {
+	Version   uint64
+	RootHash  [32]byte
+	Timestamp string
+	Metadata  map[string]string
+	Checksum  [32]byte
+}
+
+// ExportVersionProof builds a VersionProof for a committed version from
+// its root hash, recorded commit time and any metadata stored via
+// UpsertWithMetadata. Versions without metadata export an empty map.
+func (db *DB) ExportVersionProof(version uint64) (*VersionProof, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+
+	hash, err := db.HistoricalRootHash(version)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]string)
+	db.versionMeta.mu.Lock()
+	for k, v := range db.versionMeta.meta[version] {
+		metadata[k] = v
+	}
+	db.versionMeta.mu.Unlock()
+
+	proof := &VersionProof{
+		Version:   version,
+		RootHash:  hash,
+		Timestamp: db.versionTimestamp(version),
+		Metadata:  metadata,
+	}
+	proof.Checksum = proof.digest()
+	return proof, nil
+}
+
+// VerifyVersionProof checks a proof against the root hash the verifier
+// trusts, without a database. A tampered proof fails the checksum and is
+// rejected with ErrInvalidArgument; an intact proof for a different root
+// returns a *StateMismatchError carrying both hashes.
+func VerifyVersionProof(proof *VersionProof, expectedRoot [32]byte) error {
+	if proof == nil {
+		return ErrNullPointer
+	}
+	if proof.digest() != proof.Checksum {
+		return fmt.Errorf("monaddb: version proof checksum mismatch: %w", ErrInvalidArgument)
+	}
+	if proof.RootHash != expectedRoot {
+		return &StateMismatchError{Expected: expectedRoot, Actual: proof.RootHash}
+	}
+	return nil
+}
+
+// digest computes the checksum over all other proof fields. Metadata keys
+// are visited in sorted order so the digest is deterministic.
+func (p *VersionProof) digest() [32]byte {
+	h := sha256.New()
+	var version [8]byte
+	binary.BigEndian.PutUint64(version[:], p.Version)
+	h.Write(version[:])
+	h.Write(p.RootHash[:])
+	h.Write([]byte(p.Timestamp))
+
+	keys := make([]string, 0, len(p.Metadata))
+	for k := range p.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(p.Metadata[k]))
+		h.Write([]byte{0})
+	}
+
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
@@ -0,0 +1,55 @@
+package monaddb
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestVersionProof tests exporting and verifying a version proof.
+func TestVersionProof(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{{Key: makeKey32(0x01), Value: []byte("value")}}
+	root, err := db.UpsertWithMetadata(nil, updates, 1, map[string]string{
+		"producer": "node-7",
+	})
+	if err != nil {
+		t.Fatalf("UpsertWithMetadata failed: %v", err)
+	}
+
+	proof, err := db.ExportVersionProof(1)
+	if err != nil {
+		t.Fatalf("ExportVersionProof failed: %v", err)
+	}
+	if proof.Version != 1 || proof.Metadata["producer"] != "node-7" {
+		t.Errorf("Proof should carry version and metadata, got %+v", proof)
+	}
+	if proof.Timestamp == "" {
+		t.Error("Commit time should be recorded")
+	}
+
+	expected, err := root.Hash()
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if err := VerifyVersionProof(proof, expected); err != nil {
+		t.Errorf("Valid proof should verify, got %v", err)
+	}
+
+	// A different expected root is rejected with both hashes
+	var other [32]byte
+	err = VerifyVersionProof(proof, other)
+	if !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("Wrong root should return a state mismatch, got %v", err)
+	}
+
+	// Tampering breaks the checksum
+	proof.Metadata["producer"] = "node-8"
+	if err := VerifyVersionProof(proof, expected); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Tampered proof should fail the checksum, got %v", err)
+	}
+}
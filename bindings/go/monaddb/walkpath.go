@@ -0,0 +1,64 @@
+package monaddb
+
+/*
+#include "nomad_mpt.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// WalkPath descends from root toward key at the given version, invoking fn
+// for every node on the path in root-to-leaf order. The callback receives
+// the node, its depth (0 for the root) and the branch nibble taken to reach
+// it (0xFF for the root). If fn returns an error the walk stops and that
+// error is returned. This is the building block for proof generation.
+//
+// Nodes handed to fn are independent handles; callers that retain one past
+// the callback should Clone it, as the walk frees each node after fn
+// returns.
+func (db *DB) WalkPath(root *Node, key []byte, version uint64, fn func(node *Node, depth int, nibble byte) error) error {
+	if db.ptr == nil {
+		return ErrNullPointer
+	}
+	if fn == nil {
+		return ErrInvalidArgument
+	}
+
+	var rootPtr *C.NomadNode
+	if root != nil {
+		rootPtr = root.ptr
+	}
+
+	var keyPtr *C.uint8_t
+	if len(key) > 0 {
+		keyPtr = (*C.uint8_t)(unsafe.Pointer(&key[0]))
+	}
+
+	var steps *C.NomadPathStep
+	var count C.size_t
+	code := C.nomad_db_walk_path(db.ptr, rootPtr, keyPtr, C.size_t(len(key)), C.uint64_t(version), &steps, &count)
+	if err := codeToError(int(code)); err != nil {
+		return err
+	}
+	if steps == nil || count == 0 {
+		return nil
+	}
+	defer C.nomad_path_steps_free(steps)
+
+	stepSlice := unsafe.Slice(steps, int(count))
+
+	var walkErr error
+	for i := range stepSlice {
+		node := &Node{ptr: stepSlice[i].node, db: db}
+		runtime.SetFinalizer(node, (*Node).Free)
+		if walkErr == nil {
+			walkErr = fn(node, int(stepSlice[i].depth), byte(stepSlice[i].nibble))
+		}
+		// Nodes past an early stop are still freed
+		node.Free()
+	}
+	return walkErr
+}
@@ -0,0 +1,82 @@
+package monaddb
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestWalkPath tests visiting every node on the descent toward a key.
+func TestWalkPath(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x12), Value: []byte("two")},
+		{Key: makeKey32(0x22), Value: []byte("three")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	var depths []int
+	var nibbles []byte
+	err = db.WalkPath(root, makeKey32(0x11), 1, func(node *Node, depth int, nibble byte) error {
+		if node == nil {
+			t.Error("Callback received nil node")
+		}
+		depths = append(depths, depth)
+		nibbles = append(nibbles, nibble)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkPath failed: %v", err)
+	}
+
+	if len(depths) == 0 {
+		t.Fatal("Walk should visit at least the root")
+	}
+	if depths[0] != 0 || nibbles[0] != 0xFF {
+		t.Errorf("First step should be the root (depth 0, nibble 0xFF), got depth %d nibble %#x", depths[0], nibbles[0])
+	}
+	for i := 1; i < len(depths); i++ {
+		if depths[i] != depths[i-1]+1 {
+			t.Errorf("Depths should increase by one: %v", depths)
+		}
+	}
+}
+
+// TestWalkPathStop tests that a callback error stops the walk.
+func TestWalkPathStop(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	updates := []Update{
+		{Key: makeKey32(0x11), Value: []byte("one")},
+		{Key: makeKey32(0x22), Value: []byte("two")},
+	}
+	root, err := db.Upsert(nil, updates, 1)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	stop := errors.New("stop")
+	visits := 0
+	err = db.WalkPath(root, makeKey32(0x11), 1, func(node *Node, depth int, nibble byte) error {
+		visits++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if visits != 1 {
+		t.Errorf("Walk should stop after the failing callback, visited %d", visits)
+	}
+}
@@ -0,0 +1,78 @@
+package monaddb
+
+import "sync"
+
+// KeyValuePair is one entry of the recent-write log: a key, the value it
+// was set to, and the version that wrote it.
+type KeyValuePair struct {
+	Key     []byte
+	Value   []byte
+	Version uint64
+}
+
+// writeRing is a fixed-size ring of the most recent puts, newest
+// overwriting oldest. Enabled via Options.WriteLogSize.
+type writeRing struct {
+	mu      sync.Mutex
+	entries []KeyValuePair
+	next    int
+	filled  int
+}
+
+// newWriteRing returns a ring holding the last size puts.
+func newWriteRing(size int) *writeRing {
+	return &writeRing{entries: make([]KeyValuePair, size)}
+}
+
+// note appends the puts of a committed upsert; deletes are not logged.
+func (r *writeRing) note(updates []Update, version uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range updates {
+		if updates[i].Type == UpdateDelete {
+			continue
+		}
+		r.entries[r.next] = KeyValuePair{
+			Key:     append([]byte(nil), updates[i].Key...),
+			Value:   append([]byte(nil), updates[i].Value...),
+			Version: version,
+		}
+		r.next = (r.next + 1) % len(r.entries)
+		if r.filled < len(r.entries) {
+			r.filled++
+		}
+	}
+}
+
+// latest returns up to n entries, most recent first.
+func (r *writeRing) latest(n int) []KeyValuePair {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n > r.filled {
+		n = r.filled
+	}
+	out := make([]KeyValuePair, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return out
+}
+
+// GetLatestN returns the n most recently written entries across all
+// versions, newest first — a ready-made "recent transactions" feed.
+// The log is an in-memory ring enabled by Options.WriteLogSize; without
+// it the database keeps no write order and ErrNotSupported is returned.
+// Fewer than n entries come back when the log has not filled yet.
+func (db *DB) GetLatestN(n int) ([]KeyValuePair, error) {
+	if db == nil || db.ptr == nil {
+		return nil, ErrNullPointer
+	}
+	if n <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	ring := db.writeLog.Load()
+	if ring == nil {
+		return nil, ErrNotSupported
+	}
+	return ring.latest(n), nil
+}
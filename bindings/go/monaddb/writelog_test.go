@@ -0,0 +1,73 @@
+package monaddb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestGetLatestN tests the recent-write feed and its ring behavior.
+func TestGetLatestN(t *testing.T) {
+	db, err := OpenDiskWithOptions(t.TempDir()+"/log.db", Options{
+		Create:       true,
+		WriteLogSize: 4,
+	})
+	if err != nil {
+		t.Fatalf("OpenDiskWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	var root *Node
+	for v := uint64(1); v <= 6; v++ {
+		root, err = db.Put(root, makeKey32(byte(v)), []byte{byte(v)}, v)
+		if err != nil {
+			t.Fatalf("Put at version %d failed: %v", v, err)
+		}
+	}
+
+	// The ring holds 4 entries; versions 3..6 survive, newest first
+	entries, err := db.GetLatestN(10)
+	if err != nil {
+		t.Fatalf("GetLatestN failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 logged entries, got %d", len(entries))
+	}
+	for i, e := range entries {
+		want := uint64(6 - i)
+		if e.Version != want {
+			t.Errorf("Entry %d should be version %d, got %d", i, want, e.Version)
+		}
+		if !bytes.Equal(e.Key, makeKey32(byte(want))) || !bytes.Equal(e.Value, []byte{byte(want)}) {
+			t.Errorf("Entry %d carries wrong key or value", i)
+		}
+	}
+
+	// Deletes do not appear in the feed
+	if root, err = db.Delete(root, makeKey32(0x06), 7); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	entries, err = db.GetLatestN(1)
+	if err != nil {
+		t.Fatalf("GetLatestN failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Version != 6 {
+		t.Errorf("Deletes should not be logged, got %+v", entries)
+	}
+
+	if _, err := db.GetLatestN(0); err != ErrInvalidArgument {
+		t.Errorf("Zero count should be rejected, got %v", err)
+	}
+}
+
+// TestGetLatestNDisabled tests that the feed requires the write log.
+func TestGetLatestNDisabled(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.GetLatestN(5); err != ErrNotSupported {
+		t.Errorf("Without a write log GetLatestN should be unsupported, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package monaddb
+
+import (
+	"context"
+	"sync"
+)
+
+// writeGate lets all writes be suspended at once. Writers hold the read
+// side for the duration of an Upsert; pausing takes the write side, so the
+// pause begins only after in-progress writes complete.
+type writeGate struct {
+	flow sync.RWMutex
+
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{}
+}
+
+// PauseWrites suspends all new Upsert, Put and Delete calls: they block
+// inside the library until writes are resumed. In-progress writes complete
+// before the pause takes effect; PauseWrites returns once it has. Writes
+// resume when ResumeWrites is called or ctx is cancelled, whichever comes
+// first.
+//
+// This is a coarse operational backpressure knob — for example, letting an
+// RPC node starve a runaway sync loop so proof requests can be served.
+// Pausing an already-paused database returns ErrInvalidArgument.
+func (db *DB) PauseWrites(ctx context.Context) error {
+	if db == nil || db.ptr == nil {
+		return ErrNullPointer
+	}
+
+	g := &db.writes
+	g.mu.Lock()
+	if g.paused {
+		g.mu.Unlock()
+		return ErrInvalidArgument
+	}
+	g.paused = true
+	resume := make(chan struct{})
+	g.resume = resume
+	g.mu.Unlock()
+
+	g.flow.Lock() // waits for in-progress writes
+	db.log().Info("writes paused")
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-resume:
+		}
+		g.flow.Unlock()
+		g.mu.Lock()
+		g.paused = false
+		g.resume = nil
+		g.mu.Unlock()
+		db.log().Info("writes resumed")
+	}()
+	return nil
+}
+
+// ResumeWrites releases a pause started by PauseWrites, unblocking any
+// queued writers. Returns ErrInvalidArgument if writes are not paused.
+func (db *DB) ResumeWrites() error {
+	if db == nil {
+		return ErrNullPointer
+	}
+
+	g := &db.writes
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused || g.resume == nil {
+		return ErrInvalidArgument
+	}
+	close(g.resume)
+	g.resume = nil
+	return nil
+}
@@ -0,0 +1,75 @@
+package monaddb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestPauseWrites tests that writes block while paused and resume after.
+func TestPauseWrites(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.PauseWrites(context.Background()); err != nil {
+		t.Fatalf("PauseWrites failed: %v", err)
+	}
+	if err := db.PauseWrites(context.Background()); err != ErrInvalidArgument {
+		t.Errorf("Double pause should return ErrInvalidArgument, got %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := db.Put(nil, makeKey32(0x01), []byte("value"), 1); err != nil {
+			t.Errorf("Put after resume failed: %v", err)
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Write should block while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := db.ResumeWrites(); err != nil {
+		t.Fatalf("ResumeWrites failed: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write should proceed after resume")
+	}
+}
+
+// TestPauseWritesContext tests that cancelling the context lifts the pause.
+func TestPauseWritesContext(t *testing.T) {
+	db, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("OpenMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := db.PauseWrites(ctx); err != nil {
+		t.Fatalf("PauseWrites failed: %v", err)
+	}
+	cancel()
+
+	// The queued write unblocks as soon as the cancellation is observed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := db.Put(nil, makeKey32(0x02), []byte("value"), 1); err != nil {
+			t.Errorf("Put failed: %v", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Writes should resume after context cancellation")
+	}
+}
@@ -16,6 +16,7 @@
 #include <category/mpt/nibbles_view.hpp>
 #include <category/mpt/update.hpp>
 #include <category/mpt/trie.hpp>
+#include <category/mpt/traverse.hpp>
 #include <category/mpt/ondisk_db_config.hpp>
 #include <category/core/keccak.hpp>
 #include <category/core/bytes.hpp>
@@ -24,9 +25,18 @@
 
 #include <cstring>
 #include <cstdlib>
+#include <deque>
 #include <memory>
+#include <mutex>
+#include <vector>
 #include <filesystem>
 
+#ifdef __linux__
+#include <sched.h>
+#include <sys/syscall.h>
+#include <unistd.h>
+#endif
+
 // 使用 monad 命名空间
 using namespace monad;
 
@@ -96,7 +106,9 @@ struct NomadDb {
     std::unique_ptr<mpt::StateMachine> state_machine;
     bool is_on_disk;
     bool is_read_only;
-    
+    // 串行化写操作，保证 upsert_if_version 的检查+更新是原子的
+    std::mutex write_mutex;
+
     NomadDb() : is_on_disk(false), is_read_only(false) {}
 };
 
@@ -106,7 +118,20 @@ struct NomadNode {
 
 struct NomadFifo {
     NomadDb* db;
-    // TODO: 完整实现需要 ck_fifo 队列
+
+    // 同步执行模型：submit 在调用线程立即执行查询，完成结果入队等待
+    // poll。真正的 worker 纤程（ck_fifo）实现仍未完成，见头文件说明。
+    mutable std::mutex mutex;
+    std::deque<NomadCompletion> find_completions;
+    std::deque<NomadCompletion> find_completions_high;
+    std::deque<NomadCompletion> traverse_completions;
+
+    struct PendingLargeValue {
+        uint64_t user_data_lo;
+        uint64_t user_data_hi;
+        std::vector<uint8_t> data;
+    };
+    std::deque<PendingLargeValue> large_values;
 };
 
 /* ============================================================
@@ -205,10 +230,114 @@ NomadError nomad_db_open_disk(
     }
 }
 
+NomadError nomad_db_clone(const NomadDb* db, NomadDb** db_out) {
+    if (!db || !db->db || !db_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+    if (db->is_on_disk) {
+        return NOMAD_ERR_NOT_SUPPORTED;
+    }
+
+    try {
+        auto* clone = new NomadDb();
+        clone->state_machine = std::make_unique<EthereumStateMachine>();
+        clone->db = std::make_unique<mpt::Db>(*clone->state_machine);
+        clone->is_on_disk = false;
+        clone->is_read_only = false;
+
+        // 逐版本共享源库的节点；写时复制保证两侧互不影响
+        auto& src = *const_cast<NomadDb*>(db)->db;
+        uint64_t const earliest = src.get_earliest_version();
+        uint64_t const latest = src.get_latest_version();
+        if (latest != mpt::INVALID_BLOCK_NUM) {
+            for (uint64_t v = earliest; v <= latest; ++v) {
+                auto root = src.load_root_for_version(v);
+                if (!root) {
+                    continue;
+                }
+                clone->db->copy_trie(
+                    std::move(root), mpt::NibblesView{}, nullptr,
+                    mpt::NibblesView{}, v);
+            }
+        }
+
+        *db_out = clone;
+        return NOMAD_OK;
+    } catch (const std::bad_alloc&) {
+        return NOMAD_ERR_OUT_OF_MEMORY;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
 void nomad_db_close(NomadDb* db) {
     delete db;
 }
 
+NomadError nomad_db_set_io_priority(NomadDb* db, NomadIOPriority priority) {
+    if (!db || !db->db) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+#ifdef __linux__
+    // ioprio_set(IOPRIO_WHO_PROCESS, 0, ...) applies to the calling
+    // process, covering the io_uring submission threads.
+    constexpr int IOPRIO_WHO_PROCESS_ = 1;
+    constexpr int IOPRIO_CLASS_BE_ = 2;
+    constexpr int IOPRIO_CLASS_IDLE_ = 3;
+    constexpr int IOPRIO_CLASS_SHIFT_ = 13;
+
+    int ioprio;
+    switch (priority) {
+    case NOMAD_IOPRIO_HIGH:
+        ioprio = (IOPRIO_CLASS_BE_ << IOPRIO_CLASS_SHIFT_) | 0;
+        break;
+    case NOMAD_IOPRIO_NORMAL:
+        ioprio = (IOPRIO_CLASS_BE_ << IOPRIO_CLASS_SHIFT_) | 4;
+        break;
+    case NOMAD_IOPRIO_IDLE:
+        ioprio = IOPRIO_CLASS_IDLE_ << IOPRIO_CLASS_SHIFT_;
+        break;
+    default:
+        return NOMAD_ERR_INVALID_ARGUMENT;
+    }
+
+    if (syscall(SYS_ioprio_set, IOPRIO_WHO_PROCESS_, 0, ioprio) != 0) {
+        return NOMAD_ERR_IO;
+    }
+    return NOMAD_OK;
+#else
+    (void)priority;
+    return NOMAD_ERR_NOT_SUPPORTED;
+#endif
+}
+
+NomadError nomad_db_set_cpu_affinity(NomadDb* db, const int* cpus, size_t num_cpus) {
+    if (!db || !db->db || !cpus) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+    if (num_cpus == 0) {
+        return NOMAD_ERR_INVALID_ARGUMENT;
+    }
+
+#ifdef __linux__
+    cpu_set_t set;
+    CPU_ZERO(&set);
+    for (size_t i = 0; i < num_cpus; ++i) {
+        if (cpus[i] < 0 || cpus[i] >= CPU_SETSIZE) {
+            return NOMAD_ERR_INVALID_ARGUMENT;
+        }
+        CPU_SET(cpus[i], &set);
+    }
+    if (sched_setaffinity(0, sizeof(set), &set) != 0) {
+        return NOMAD_ERR_IO;
+    }
+    return NOMAD_OK;
+#else
+    return NOMAD_ERR_NOT_SUPPORTED;
+#endif
+}
+
 bool nomad_db_is_on_disk(const NomadDb* db) {
     if (!db || !db->db) return false;
     return db->is_on_disk;
@@ -322,6 +451,47 @@ bool nomad_node_has_value(const NomadNode* node) {
     return node->node->has_value();
 }
 
+NomadNodeType nomad_node_type(const NomadNode* node) {
+    if (!node || !node->node) return NOMAD_NODE_LEAF;
+    if (node->node->number_of_children() == 0) {
+        return NOMAD_NODE_LEAF;
+    }
+    if (node->node->path_nibbles_len() > 0) {
+        return NOMAD_NODE_EXTENSION;
+    }
+    return NOMAD_NODE_BRANCH;
+}
+
+uint16_t nomad_node_child_mask(const NomadNode* node) {
+    if (!node || !node->node) return 0;
+    return node->node->mask;
+}
+
+NomadError nomad_node_child_hash(const NomadNode* node, uint8_t branch, uint8_t* hash_out) {
+    if (!node || !node->node || !hash_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+    if (branch >= 16) {
+        return NOMAD_ERR_INVALID_ARGUMENT;
+    }
+
+    try {
+        auto const& n = *node->node;
+        if (!(n.mask & (1u << branch))) {
+            return NOMAD_ERR_NOT_FOUND;
+        }
+        auto const data = n.child_data_view(n.to_child_index(branch));
+        if (data.size() >= KECCAK256_SIZE) {
+            std::memcpy(hash_out, data.data(), KECCAK256_SIZE);
+        } else {
+            keccak256(data.data(), data.size(), hash_out);
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
 NomadError nomad_node_get_hash(const NomadNode* node, uint8_t* hash_out) {
     if (!node || !hash_out) {
         return NOMAD_ERR_NULL_POINTER;
@@ -348,6 +518,19 @@ NomadError nomad_node_get_hash(const NomadNode* node, uint8_t* hash_out) {
     }
 }
 
+NomadError nomad_keccak256(const uint8_t* input, size_t input_len, uint8_t* hash_out) {
+    if (!hash_out || (!input && input_len > 0)) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    try {
+        keccak256(input, input_len, hash_out);
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
 NomadNode* nomad_node_clone(const NomadNode* node) {
     if (!node) return nullptr;
     
@@ -360,6 +543,62 @@ NomadNode* nomad_node_clone(const NomadNode* node) {
     }
 }
 
+NomadError nomad_node_with_value(
+    const NomadNode* node,
+    const uint8_t* value,
+    size_t value_len,
+    NomadNode** node_out
+) {
+    if (!node || !node_out || (!value && value_len > 0)) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+    if (!node->node) {
+        return NOMAD_ERR_NOT_FOUND;
+    }
+
+    try {
+        byte_string_view value_view{value, value_len};
+        auto fresh = make_node(
+            *node->node,
+            node->node->path_nibble_view(),
+            std::optional<byte_string_view>{value_view},
+            node->node->version);
+        if (!fresh) {
+            return NOMAD_ERR_OUT_OF_MEMORY;
+        }
+
+        auto* out = new NomadNode();
+        out->node = std::move(fresh);
+        *node_out = out;
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+NomadError nomad_node_empty(NomadNode** node_out) {
+    if (!node_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    try {
+        // 无 path、无 value、无 children 的节点即规范空 trie 根
+        auto empty = mpt::make_node(
+            0, std::span<mpt::ChildData>{}, mpt::NibblesView{},
+            std::nullopt, size_t{0}, 0);
+        if (!empty) {
+            return NOMAD_ERR_OUT_OF_MEMORY;
+        }
+
+        auto* out = new NomadNode();
+        out->node = std::move(empty);
+        *node_out = out;
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
 void nomad_node_free(NomadNode* node) {
     delete node;
 }
@@ -407,7 +646,8 @@ static mpt::UpdateList build_update_list(
     return list;
 }
 
-NomadError nomad_db_upsert(
+/// 执行 upsert 主体（调用方负责参数检查和加锁）
+static NomadError do_upsert(
     NomadDb* db,
     const NomadNode* root,
     const NomadUpdate* updates,
@@ -415,23 +655,15 @@ NomadError nomad_db_upsert(
     uint64_t version,
     NomadNode** new_root_out
 ) {
-    if (!db || !db->db || !new_root_out) {
-        return NOMAD_ERR_NULL_POINTER;
-    }
-    
-    if (updates_len > 0 && !updates) {
-        return NOMAD_ERR_NULL_POINTER;
-    }
-    
     try {
         mpt::Node::SharedPtr root_node;
         if (root && root->node) {
             root_node = root->node;
         }
-        
+
         UpdateStorage storage;
         auto update_list = build_update_list(updates, updates_len, storage);
-        
+
         auto new_root = db->db->upsert(
             root_node,
             std::move(update_list),
@@ -440,7 +672,7 @@ NomadError nomad_db_upsert(
             true,   // can_write_to_fast
             false   // write_root
         );
-        
+
         auto* node = new NomadNode();
         node->node = std::move(new_root);
         *new_root_out = node;
@@ -450,112 +682,1054 @@ NomadError nomad_db_upsert(
     }
 }
 
-/* ============================================================
- * Version Management
- * ============================================================ */
+NomadError nomad_db_upsert(
+    NomadDb* db,
+    const NomadNode* root,
+    const NomadUpdate* updates,
+    size_t updates_len,
+    uint64_t version,
+    NomadNode** new_root_out
+) {
+    if (!db || !db->db || !new_root_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
 
-uint64_t nomad_db_get_latest_version(const NomadDb* db) {
-    if (!db || !db->db) return 0;
-    try {
-        if (db->is_on_disk) {
-            return db->db->get_latest_version();
-        }
-        return 0;
-    } catch (...) {
-        return 0;
+    if (updates_len > 0 && !updates) {
+        return NOMAD_ERR_NULL_POINTER;
     }
+
+    std::lock_guard<std::mutex> lock(db->write_mutex);
+    return do_upsert(db, root, updates, updates_len, version, new_root_out);
 }
 
-uint64_t nomad_db_get_earliest_version(const NomadDb* db) {
-    if (!db || !db->db) return 0;
+NomadError nomad_db_upsert_if_version(
+    NomadDb* db,
+    const NomadNode* root,
+    const NomadUpdate* updates,
+    size_t updates_len,
+    uint64_t expected_version,
+    uint64_t new_version,
+    NomadNode** new_root_out
+) {
+    if (!db || !db->db || !new_root_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    if (updates_len > 0 && !updates) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    std::lock_guard<std::mutex> lock(db->write_mutex);
+
     try {
+        uint64_t latest = 0;
         if (db->is_on_disk) {
-            return db->db->get_earliest_version();
+            latest = db->db->get_latest_version();
+        }
+        if (latest != expected_version) {
+            return NOMAD_ERR_VERSION_CONFLICT;
         }
-        return 0;
     } catch (...) {
-        return 0;
+        return NOMAD_ERR_INTERNAL;
     }
-}
 
-uint64_t nomad_db_get_history_length(const NomadDb* db) {
-    if (!db || !db->db) return 0;
-    try {
-        return db->db->get_history_length();
-    } catch (...) {
-        return 0;
-    }
+    return do_upsert(db, root, updates, updates_len, new_version, new_root_out);
 }
 
-NomadError nomad_db_load_root(const NomadDb* db, uint64_t version, NomadNode** root_out) {
-    if (!db || !db->db || !root_out) {
+NomadError nomad_db_graft_subtree(
+    NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* prefix,
+    size_t prefix_len,
+    const NomadNode* subtree_root,
+    uint64_t version,
+    NomadNode** new_root_out
+) {
+    if (!db || !db->db || !subtree_root || !subtree_root->node ||
+        !new_root_out || (!prefix && prefix_len > 0)) {
         return NOMAD_ERR_NULL_POINTER;
     }
-    
+
+    std::lock_guard<std::mutex> lock(db->write_mutex);
+
     try {
-        auto root = db->db->load_root_for_version(version);
-        if (root) {
-            auto* node = new NomadNode();
-            node->node = std::move(root);
-            *root_out = node;
-        } else {
-            *root_out = nullptr;
+        mpt::Node::SharedPtr root_node;
+        if (root && root->node) {
+            root_node = root->node;
         }
+
+        byte_string_view prefix_bytes{prefix, prefix_len};
+        auto new_root = db->db->copy_trie(
+            subtree_root->node,
+            mpt::NibblesView{},
+            std::move(root_node),
+            mpt::NibblesView{prefix_bytes},
+            version,
+            false  // write_root
+        );
+
+        auto* node = new NomadNode();
+        node->node = std::move(new_root);
+        *new_root_out = node;
         return NOMAD_OK;
     } catch (...) {
         return NOMAD_ERR_INTERNAL;
     }
 }
 
-bool nomad_db_version_is_valid(const NomadDb* db, uint64_t version) {
-    if (!db || !db->db) return false;
-    
-    if (!db->is_on_disk) {
-        return true;
+NomadError nomad_db_extract_subtree(
+    NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* prefix,
+    size_t prefix_len,
+    uint64_t version,
+    NomadNode** new_root_out
+) {
+    if (!db || !db->db || !root || !root->node || !new_root_out ||
+        (!prefix && prefix_len > 0)) {
+        return NOMAD_ERR_NULL_POINTER;
     }
-    
+
+    std::lock_guard<std::mutex> lock(db->write_mutex);
+
     try {
-        auto earliest = db->db->get_earliest_version();
-        auto latest = db->db->get_latest_version();
-        return version >= earliest && version <= latest;
+        byte_string_view prefix_bytes{prefix, prefix_len};
+        auto new_root = db->db->copy_trie(
+            root->node,
+            mpt::NibblesView{prefix_bytes},
+            nullptr,
+            mpt::NibblesView{},
+            version,
+            false  // write_root
+        );
+
+        if (!new_root) {
+            *new_root_out = nullptr;
+            return NOMAD_OK;
+        }
+
+        auto* node = new NomadNode();
+        node->node = std::move(new_root);
+        *new_root_out = node;
+        return NOMAD_OK;
     } catch (...) {
-        return false;
+        return NOMAD_ERR_INTERNAL;
     }
 }
 
 /* ============================================================
- * Async FIFO Operations
+ * Key Navigation
  * ============================================================ */
 
-NomadError nomad_fifo_create(NomadDb* db, NomadFifo** fifo_out) {
-    if (!db || !fifo_out) {
-        return NOMAD_ERR_NULL_POINTER;
+namespace {
+
+/// 把 nibble 路径转换为字节 key（key 长度固定为偶数个 nibble）
+NomadBytes nibbles_to_key_bytes(mpt::NibblesView const path) {
+    NomadBytes out = {nullptr, 0};
+    unsigned const n = path.nibble_size();
+    if (n == 0) {
+        return out;
     }
-    
-    try {
-        auto* fifo = new NomadFifo();
-        fifo->db = db;
-        *fifo_out = fifo;
-        return NOMAD_OK;
-    } catch (...) {
-        return NOMAD_ERR_OUT_OF_MEMORY;
+    size_t const len = (n + 1) / 2;
+    out.data = static_cast<uint8_t*>(std::malloc(len));
+    if (!out.data) {
+        return out;
+    }
+    for (unsigned i = 0; i < n; i += 2) {
+        uint8_t b = static_cast<uint8_t>(path.get(i) << 4);
+        if (i + 1 < n) {
+            b |= path.get(i + 1);
+        }
+        out.data[i / 2] = b;
     }
+    out.len = len;
+    return out;
 }
 
-void nomad_fifo_start(NomadFifo* fifo, size_t num_workers) {
-    (void)fifo;
-    (void)num_workers;
-    // TODO: 实现 FIFO worker
-}
+/// CollectMachine - 按升序收集 prefix 下的 key-value 对
+/// 支持 start_after 续传和 limit 截断，用于分页读取大 trie
+class CollectMachine final : public mpt::TraverseMachine {
+    mpt::Nibbles path_{};
+    mpt::Nibbles prefix_;
+    mpt::Nibbles start_after_;
+    bool const has_start_after_;
+    size_t const limit_;  // 0 = 不限
+    int64_t const min_version_;  // 0 = 不按版本剪枝
+    bool done_{false};
+    std::vector<NomadKV> items_;
 
-void nomad_fifo_stop(NomadFifo* fifo) {
-    (void)fifo;
-}
+public:
+    CollectMachine(
+        mpt::NibblesView prefix, mpt::NibblesView start_after,
+        bool has_start_after, size_t limit, int64_t min_version)
+        : prefix_{prefix}
+        , start_after_{start_after}
+        , has_start_after_(has_start_after)
+        , limit_(limit)
+        , min_version_(min_version)
+    {}
+
+    CollectMachine(CollectMachine const&) = default;
+
+    bool down(unsigned char const branch, mpt::Node const& node) override {
+        if (done_) {
+            return false;
+        }
+        // 内部节点版本 >= 其子树所有叶子的版本，低于阈值的子树可整体剪掉。
+        // 在更新 path_ 之前返回，保持路径栈与 up() 配对一致
+        if (node.version < min_version_) {
+            return false;
+        }
+        if (branch == mpt::INVALID_BRANCH) {
+            path_ = mpt::Nibbles{node.path_nibble_view()};
+        } else {
+            path_ = mpt::concat(
+                mpt::NibblesView{path_}, branch, node.path_nibble_view());
+        }
+        if (node.has_value()) {
+            auto const path_view = mpt::NibblesView{path_};
+            bool matches = path_view.starts_with(mpt::NibblesView{prefix_});
+            if (matches && has_start_after_) {
+                matches = path_view > mpt::NibblesView{start_after_};
+            }
+            if (matches) {
+                NomadKV kv;
+                kv.key = nibbles_to_key_bytes(path_view);
+                kv.value = make_bytes(node.value());
+                kv.version = static_cast<uint64_t>(node.version);
+                items_.push_back(kv);
+                if (limit_ > 0 && items_.size() >= limit_) {
+                    done_ = true;
+                    return false;
+                }
+            }
+        }
+        return true;
+    }
+
+    void up(unsigned char const branch, mpt::Node const& node) override {
+        auto const path_view = mpt::NibblesView{path_};
+        unsigned const prefix_size =
+            branch == mpt::INVALID_BRANCH
+                ? 0
+                : path_view.nibble_size() - node.path_nibbles_len() - 1;
+        path_ = path_view.substr(0, prefix_size);
+    }
+
+    bool should_visit(mpt::Node const&, unsigned char const branch) override {
+        if (done_) {
+            return false;
+        }
+        auto const child = mpt::concat(mpt::NibblesView{path_}, branch);
+        auto const child_view = mpt::NibblesView{child};
+        auto const prefix_view = mpt::NibblesView{prefix_};
+        // 子树必须与 prefix 兼容（互为前缀）
+        if (!child_view.starts_with(prefix_view) &&
+            !prefix_view.starts_with(child_view)) {
+            return false;
+        }
+        if (has_start_after_) {
+            auto const after_view = mpt::NibblesView{start_after_};
+            // 子树完全小于等于 start_after 时剪枝
+            if (!after_view.starts_with(child_view) &&
+                child_view < after_view) {
+                return false;
+            }
+        }
+        return true;
+    }
+
+    std::unique_ptr<mpt::TraverseMachine> clone() const override {
+        return std::make_unique<CollectMachine>(*this);
+    }
+
+    std::vector<NomadKV>& items() { return items_; }
+};
+
+/// EdgeKeyMachine - 沿最左/最右分支下降，找到最小/最大 key
+/// 不扫描整棵 trie，每层只访问一个子节点
+class EdgeKeyMachine final : public mpt::TraverseMachine {
+    mpt::Nibbles path_{};
+    bool const rightmost_;
+    bool done_{false};
+    std::optional<mpt::Nibbles> result_{};
+
+public:
+    explicit EdgeKeyMachine(bool rightmost) : rightmost_(rightmost) {}
+
+    EdgeKeyMachine(EdgeKeyMachine const&) = default;
+
+    bool down(unsigned char const branch, mpt::Node const& node) override {
+        if (branch == mpt::INVALID_BRANCH) {
+            path_ = mpt::Nibbles{node.path_nibble_view()};
+        } else {
+            path_ = mpt::concat(
+                mpt::NibblesView{path_}, branch, node.path_nibble_view());
+        }
+        if (node.has_value()) {
+            result_ = path_;
+            done_ = true;
+            return false;
+        }
+        return true;
+    }
+
+    void up(unsigned char const branch, mpt::Node const& node) override {
+        auto const path_view = mpt::NibblesView{path_};
+        unsigned const prefix_size =
+            branch == mpt::INVALID_BRANCH
+                ? 0
+                : path_view.nibble_size() - node.path_nibbles_len() - 1;
+        path_ = path_view.substr(0, prefix_size);
+    }
+
+    bool should_visit(mpt::Node const& node, unsigned char const branch) override {
+        if (done_) {
+            return false;
+        }
+        // 只访问最小/最大的有效分支
+        if (rightmost_) {
+            for (unsigned b = 15; b > branch; --b) {
+                if (node.mask & (1u << b)) {
+                    return false;
+                }
+            }
+        } else {
+            for (unsigned b = 0; b < branch; ++b) {
+                if (node.mask & (1u << b)) {
+                    return false;
+                }
+            }
+        }
+        return true;
+    }
+
+    std::unique_ptr<mpt::TraverseMachine> clone() const override {
+        return std::make_unique<EdgeKeyMachine>(*this);
+    }
+
+    std::optional<mpt::Nibbles> const& result() const { return result_; }
+};
+
+/// BoundKeyMachine - 查找严格大于/小于 target 的相邻 key
+/// 利用 should_visit 剪枝，只访问边界路径附近的子树
+class BoundKeyMachine final : public mpt::TraverseMachine {
+    mpt::Nibbles path_{};
+    mpt::Nibbles target_;
+    bool const forward_;  // true: 最小的大于 target 的 key；false: 最大的小于 target 的 key
+    bool done_{false};
+    std::optional<mpt::Nibbles> result_{};
+
+public:
+    BoundKeyMachine(mpt::NibblesView target, bool forward)
+        : target_{target}, forward_(forward) {}
+
+    BoundKeyMachine(BoundKeyMachine const&) = default;
+
+    bool down(unsigned char const branch, mpt::Node const& node) override {
+        if (done_) {
+            return false;
+        }
+        if (branch == mpt::INVALID_BRANCH) {
+            path_ = mpt::Nibbles{node.path_nibble_view()};
+        } else {
+            path_ = mpt::concat(
+                mpt::NibblesView{path_}, branch, node.path_nibble_view());
+        }
+        if (node.has_value()) {
+            auto const path_view = mpt::NibblesView{path_};
+            auto const target_view = mpt::NibblesView{target_};
+            if (forward_) {
+                if (path_view > target_view) {
+                    // 升序遍历中第一个大于 target 的 key 即为答案
+                    result_ = path_;
+                    done_ = true;
+                    return false;
+                }
+            } else {
+                if (path_view < target_view) {
+                    // 持续更新，最后一个小于 target 的 key 即为答案
+                    result_ = path_;
+                } else {
+                    // 已越过 target，后续 key 只会更大
+                    done_ = true;
+                    return false;
+                }
+            }
+        }
+        return true;
+    }
+
+    void up(unsigned char const branch, mpt::Node const& node) override {
+        auto const path_view = mpt::NibblesView{path_};
+        unsigned const prefix_size =
+            branch == mpt::INVALID_BRANCH
+                ? 0
+                : path_view.nibble_size() - node.path_nibbles_len() - 1;
+        path_ = path_view.substr(0, prefix_size);
+    }
+
+    bool should_visit(mpt::Node const& node, unsigned char const branch) override {
+        if (done_) {
+            return false;
+        }
+        auto const child = mpt::concat(mpt::NibblesView{path_}, branch);
+        auto const child_view = mpt::NibblesView{child};
+        auto const target_view = mpt::NibblesView{target_};
+        if (forward_) {
+            // 子树所有 key 都以 child 为前缀：完全小于 target 时剪枝
+            return target_view.starts_with(child_view) ||
+                   child_view > target_view;
+        }
+        if (!target_view.starts_with(child_view) && child_view > target_view) {
+            return false;  // 完全大于 target
+        }
+        auto const path_view = mpt::NibblesView{path_};
+        if (!target_view.starts_with(path_view) && path_view < target_view) {
+            // 当前子树整体小于 target，只需要其最右侧的 key
+            for (unsigned b = 15; b > branch; --b) {
+                if (node.mask & (1u << b)) {
+                    return false;
+                }
+            }
+        }
+        return true;
+    }
+
+    std::unique_ptr<mpt::TraverseMachine> clone() const override {
+        return std::make_unique<BoundKeyMachine>(*this);
+    }
+
+    std::optional<mpt::Nibbles> const& result() const { return result_; }
+};
+
+NomadError bound_key_impl(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    bool forward,
+    NomadBytes* key_out
+) {
+    if (!db || !db->db || !key_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    key_out->data = nullptr;
+    key_out->len = 0;
+
+    if (!root || !root->node) {
+        return NOMAD_OK;  // 空 trie
+    }
+
+    try {
+        byte_string_view key_bytes{key, key_len};
+        BoundKeyMachine machine{mpt::NibblesView{key_bytes}, forward};
+        mpt::NodeCursor cursor{root->node};
+        const_cast<NomadDb*>(db)->db->traverse_blocking(
+            cursor, machine, version);
+
+        if (machine.result()) {
+            *key_out = nibbles_to_key_bytes(mpt::NibblesView{*machine.result()});
+            if (!key_out->data && machine.result()->nibble_size() > 0) {
+                return NOMAD_ERR_OUT_OF_MEMORY;
+            }
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+NomadError edge_key_impl(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t version,
+    bool rightmost,
+    NomadBytes* key_out
+) {
+    if (!db || !db->db || !key_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    key_out->data = nullptr;
+    key_out->len = 0;
+
+    if (!root || !root->node) {
+        return NOMAD_OK;  // 空 trie
+    }
+
+    try {
+        EdgeKeyMachine machine{rightmost};
+        mpt::NodeCursor cursor{root->node};
+        const_cast<NomadDb*>(db)->db->traverse_blocking(
+            cursor, machine, version);
+
+        if (machine.result()) {
+            *key_out = nibbles_to_key_bytes(mpt::NibblesView{*machine.result()});
+            if (!key_out->data && machine.result()->nibble_size() > 0) {
+                return NOMAD_ERR_OUT_OF_MEMORY;
+            }
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+} // anonymous namespace
+
+NomadError nomad_db_first_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t version,
+    NomadBytes* key_out
+) {
+    return edge_key_impl(db, root, version, false, key_out);
+}
+
+NomadError nomad_db_last_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t version,
+    NomadBytes* key_out
+) {
+    return edge_key_impl(db, root, version, true, key_out);
+}
+
+NomadError nomad_db_next_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    NomadBytes* key_out
+) {
+    return bound_key_impl(db, root, key, key_len, version, true, key_out);
+}
+
+NomadError nomad_db_prev_key(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    NomadBytes* key_out
+) {
+    return bound_key_impl(db, root, key, key_len, version, false, key_out);
+}
+
+void nomad_kv_list_free(NomadKVList* list) {
+    if (!list || !list->items) {
+        return;
+    }
+    for (size_t i = 0; i < list->len; ++i) {
+        nomad_bytes_free(&list->items[i].key);
+        nomad_bytes_free(&list->items[i].value);
+    }
+    std::free(list->items);
+    list->items = nullptr;
+    list->len = 0;
+}
+
+NomadError nomad_db_traverse_sync(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* prefix,
+    size_t prefix_len,
+    const uint8_t* start_after,
+    size_t start_after_len,
+    size_t limit,
+    uint64_t version,
+    NomadKVList* list_out
+) {
+    if (!db || !db->db || !list_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    list_out->items = nullptr;
+    list_out->len = 0;
+
+    if (!root || !root->node) {
+        return NOMAD_OK;  // 空 trie
+    }
+
+    try {
+        byte_string_view prefix_bytes{prefix, prefix_len};
+        byte_string_view after_bytes{start_after, start_after_len};
+        CollectMachine machine{
+            mpt::NibblesView{prefix_bytes},
+            mpt::NibblesView{after_bytes},
+            start_after != nullptr,
+            limit,
+            0};
+        mpt::NodeCursor cursor{root->node};
+        const_cast<NomadDb*>(db)->db->traverse_blocking(
+            cursor, machine, version);
+
+        auto& items = machine.items();
+        if (!items.empty()) {
+            list_out->items = static_cast<NomadKV*>(
+                std::malloc(items.size() * sizeof(NomadKV)));
+            if (!list_out->items) {
+                for (auto& kv : items) {
+                    nomad_bytes_free(&kv.key);
+                    nomad_bytes_free(&kv.value);
+                }
+                return NOMAD_ERR_OUT_OF_MEMORY;
+            }
+            std::memcpy(
+                list_out->items, items.data(),
+                items.size() * sizeof(NomadKV));
+            list_out->len = items.size();
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+NomadError nomad_db_traverse_since(
+    const NomadDb* db,
+    const NomadNode* root,
+    uint64_t min_version,
+    const uint8_t* start_after,
+    size_t start_after_len,
+    size_t limit,
+    uint64_t version,
+    NomadKVList* list_out
+) {
+    if (!db || !db->db || !list_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    list_out->items = nullptr;
+    list_out->len = 0;
+
+    if (!root || !root->node) {
+        return NOMAD_OK;  // 空 trie
+    }
+
+    try {
+        byte_string_view after_bytes{start_after, start_after_len};
+        CollectMachine machine{
+            mpt::NibblesView{},
+            mpt::NibblesView{after_bytes},
+            start_after != nullptr,
+            limit,
+            static_cast<int64_t>(min_version)};
+        mpt::NodeCursor cursor{root->node};
+        const_cast<NomadDb*>(db)->db->traverse_blocking(
+            cursor, machine, version);
+
+        auto& items = machine.items();
+        if (!items.empty()) {
+            list_out->items = static_cast<NomadKV*>(
+                std::malloc(items.size() * sizeof(NomadKV)));
+            if (!list_out->items) {
+                for (auto& kv : items) {
+                    nomad_bytes_free(&kv.key);
+                    nomad_bytes_free(&kv.value);
+                }
+                return NOMAD_ERR_OUT_OF_MEMORY;
+            }
+            std::memcpy(
+                list_out->items, items.data(),
+                items.size() * sizeof(NomadKV));
+            list_out->len = items.size();
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+namespace {
+
+/// PathMachine - 沿目标 key 的 nibble 路径下降，收集途经节点
+/// 每个节点以副本形式返回，调用方独立持有句柄
+class PathMachine final : public mpt::TraverseMachine {
+public:
+    struct Step {
+        mpt::Node::SharedPtr node;
+        uint32_t depth;
+        uint8_t nibble;
+    };
+
+private:
+    mpt::Nibbles target_;
+    mpt::Nibbles path_{};
+    uint32_t depth_{0};
+    std::vector<Step> steps_;
+
+public:
+    explicit PathMachine(mpt::NibblesView target) : target_{target} {}
+
+    PathMachine(PathMachine const&) = default;
+
+    bool down(unsigned char const branch, mpt::Node const& node) override {
+        if (branch == mpt::INVALID_BRANCH) {
+            path_ = mpt::Nibbles{node.path_nibble_view()};
+        } else {
+            path_ = mpt::concat(
+                mpt::NibblesView{path_}, branch, node.path_nibble_view());
+        }
+
+        auto copy = mpt::make_node(
+            const_cast<mpt::Node&>(node),
+            node.path_nibble_view(),
+            node.has_value()
+                ? std::optional<byte_string_view>{node.value()}
+                : std::nullopt,
+            node.version);
+        Step step;
+        step.node = std::move(copy);
+        step.depth = depth_;
+        step.nibble = branch == mpt::INVALID_BRANCH
+            ? static_cast<uint8_t>(0xFF)
+            : static_cast<uint8_t>(branch);
+        steps_.push_back(std::move(step));
+        ++depth_;
+
+        // 路径偏离目标 key 时停止下降
+        return mpt::NibblesView{target_}.starts_with(mpt::NibblesView{path_});
+    }
+
+    void up(unsigned char const branch, mpt::Node const& node) override {
+        auto const path_view = mpt::NibblesView{path_};
+        unsigned const prefix_size =
+            branch == mpt::INVALID_BRANCH
+                ? 0
+                : path_view.nibble_size() - node.path_nibbles_len() - 1;
+        path_ = path_view.substr(0, prefix_size);
+        if (depth_ > 0) {
+            --depth_;
+        }
+    }
+
+    bool should_visit(mpt::Node const&, unsigned char const branch) override {
+        auto const child = mpt::concat(mpt::NibblesView{path_}, branch);
+        return mpt::NibblesView{target_}.starts_with(mpt::NibblesView{child});
+    }
+
+    std::unique_ptr<mpt::TraverseMachine> clone() const override {
+        return std::make_unique<PathMachine>(*this);
+    }
+
+    std::vector<Step>& steps() { return steps_; }
+};
+
+} // namespace
+
+NomadError nomad_db_walk_path(
+    const NomadDb* db,
+    const NomadNode* root,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    NomadPathStep** steps_out,
+    size_t* count_out
+) {
+    if (!db || !db->db || !steps_out || !count_out || (!key && key_len > 0)) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    *steps_out = nullptr;
+    *count_out = 0;
+
+    if (!root || !root->node) {
+        return NOMAD_OK;  // 空 trie
+    }
+
+    try {
+        byte_string_view key_bytes{key, key_len};
+        PathMachine machine{mpt::NibblesView{key_bytes}};
+        mpt::NodeCursor cursor{root->node};
+        const_cast<NomadDb*>(db)->db->traverse_blocking(
+            cursor, machine, version);
+
+        auto& steps = machine.steps();
+        if (!steps.empty()) {
+            *steps_out = static_cast<NomadPathStep*>(
+                std::malloc(steps.size() * sizeof(NomadPathStep)));
+            if (!*steps_out) {
+                return NOMAD_ERR_OUT_OF_MEMORY;
+            }
+            for (size_t i = 0; i < steps.size(); ++i) {
+                auto* handle = new NomadNode();
+                handle->node = std::move(steps[i].node);
+                (*steps_out)[i].node = handle;
+                (*steps_out)[i].depth = steps[i].depth;
+                (*steps_out)[i].nibble = steps[i].nibble;
+            }
+            *count_out = steps.size();
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+void nomad_path_steps_free(NomadPathStep* steps) {
+    std::free(steps);
+}
+
+/* ============================================================
+ * Version Management
+ * ============================================================ */
+
+uint64_t nomad_db_get_latest_version(const NomadDb* db) {
+    if (!db || !db->db) return 0;
+    try {
+        if (db->is_on_disk) {
+            return db->db->get_latest_version();
+        }
+        return 0;
+    } catch (...) {
+        return 0;
+    }
+}
+
+uint64_t nomad_db_get_earliest_version(const NomadDb* db) {
+    if (!db || !db->db) return 0;
+    try {
+        if (db->is_on_disk) {
+            return db->db->get_earliest_version();
+        }
+        return 0;
+    } catch (...) {
+        return 0;
+    }
+}
+
+uint64_t nomad_db_get_history_length(const NomadDb* db) {
+    if (!db || !db->db) return 0;
+    try {
+        return db->db->get_history_length();
+    } catch (...) {
+        return 0;
+    }
+}
+
+NomadError nomad_db_load_root(const NomadDb* db, uint64_t version, NomadNode** root_out) {
+    if (!db || !db->db || !root_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+    
+    try {
+        auto root = db->db->load_root_for_version(version);
+        if (root) {
+            auto* node = new NomadNode();
+            node->node = std::move(root);
+            *root_out = node;
+        } else {
+            *root_out = nullptr;
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+NomadError nomad_db_load_root_batch(const NomadDb* db, const uint64_t* versions,
+                                    size_t count, NomadNode** roots_out,
+                                    size_t* loaded_out) {
+    if (!db || !db->db || !versions || !roots_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    size_t loaded = 0;
+    try {
+        for (size_t i = 0; i < count; i++) {
+            auto root = db->db->load_root_for_version(versions[i]);
+            if (root) {
+                auto* node = new NomadNode();
+                node->node = std::move(root);
+                roots_out[i] = node;
+            } else {
+                roots_out[i] = nullptr;
+            }
+            loaded++;
+        }
+        if (loaded_out) {
+            *loaded_out = loaded;
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        // 释放已经装载的节点句柄，保持失败时无泄漏
+        for (size_t i = 0; i < loaded; i++) {
+            delete roots_out[i];
+            roots_out[i] = nullptr;
+        }
+        if (loaded_out) {
+            *loaded_out = loaded;
+        }
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+NomadError nomad_db_latest_root(const NomadDb* db, uint64_t* version_out, NomadNode** root_out) {
+    if (!db || !db->db || !root_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    try {
+        uint64_t version = 0;
+        if (db->is_on_disk) {
+            version = db->db->get_latest_version();
+        }
+        if (version_out) {
+            *version_out = version;
+        }
+
+        auto root = db->db->load_root_for_version(version);
+        if (root) {
+            auto* node = new NomadNode();
+            node->node = std::move(root);
+            *root_out = node;
+        } else {
+            *root_out = nullptr;
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+NomadError nomad_db_root_hash(const NomadDb* db, uint64_t version, uint8_t* hash_out) {
+    if (!db || !db->db || !hash_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+
+    try {
+        auto root = db->db->load_root_for_version(version);
+        if (!root) {
+            return NOMAD_ERR_NOT_FOUND;
+        }
+
+        EthMerkleCompute compute;
+        unsigned char buffer[532];
+
+        unsigned len = compute.compute(buffer, root.get());
+
+        if (len < KECCAK256_SIZE) {
+            keccak256(buffer, len, hash_out);
+        } else {
+            std::memcpy(hash_out, buffer, KECCAK256_SIZE);
+        }
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_INTERNAL;
+    }
+}
+
+bool nomad_db_version_is_valid(const NomadDb* db, uint64_t version) {
+    if (!db || !db->db) return false;
+    
+    if (!db->is_on_disk) {
+        return true;
+    }
+    
+    try {
+        auto earliest = db->db->get_earliest_version();
+        auto latest = db->db->get_latest_version();
+        return version >= earliest && version <= latest;
+    } catch (...) {
+        return false;
+    }
+}
+
+/* ============================================================
+ * Async FIFO Operations
+ * ============================================================ */
+
+NomadError nomad_fifo_create(NomadDb* db, NomadFifo** fifo_out) {
+    if (!db || !fifo_out) {
+        return NOMAD_ERR_NULL_POINTER;
+    }
+    
+    try {
+        auto* fifo = new NomadFifo();
+        fifo->db = db;
+        *fifo_out = fifo;
+        return NOMAD_OK;
+    } catch (...) {
+        return NOMAD_ERR_OUT_OF_MEMORY;
+    }
+}
+
+void nomad_fifo_start(NomadFifo* fifo, size_t num_workers) {
+    (void)fifo;
+    (void)num_workers;
+    // TODO: 实现 FIFO worker
+}
+
+void nomad_fifo_stop(NomadFifo* fifo) {
+    (void)fifo;
+}
 
 void nomad_fifo_destroy(NomadFifo* fifo) {
     delete fifo;
 }
 
+/// 同步执行一次查找并把完成结果排入对应优先级的队列
+static void fifo_execute_find(
+    NomadFifo* fifo,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    uint64_t user_data_lo,
+    uint64_t user_data_hi,
+    int priority
+) {
+    NomadCompletion comp;
+    std::memset(&comp, 0, sizeof(comp));
+    comp.user_data_lo = user_data_lo;
+    comp.user_data_hi = user_data_hi;
+    comp.priority = priority;
+
+    std::vector<uint8_t> large;
+    try {
+        if (!fifo->db || !fifo->db->db || !fifo->db->is_on_disk) {
+            // 内存模式没有按 key 的全局查找（参见 nomad_db_find）
+            comp.status = NOMAD_STATUS_ERROR;
+        } else {
+            byte_string_view key_bytes{key, key_len};
+            mpt::NibblesView key_view{key_bytes};
+            auto result = fifo->db->db->find(key_view, version);
+            if (result.has_error() || !result.value().node ||
+                !result.value().node->has_value()) {
+                comp.status = NOMAD_STATUS_NOT_FOUND;
+            } else {
+                auto value = result.value().node->value();
+                comp.status = NOMAD_STATUS_OK;
+                if (value.size() <= sizeof(comp.value)) {
+                    comp.value_len = static_cast<uint32_t>(value.size());
+                    if (!value.empty()) {
+                        std::memcpy(comp.value, value.data(), value.size());
+                    }
+                } else {
+                    // 大值经由 large value 队列返回，completion 只带状态
+                    large.assign(value.begin(), value.end());
+                }
+            }
+        }
+    } catch (...) {
+        comp.status = NOMAD_STATUS_ERROR;
+    }
+
+    std::lock_guard<std::mutex> lock(fifo->mutex);
+    if (priority == 1) {
+        fifo->find_completions_high.push_back(comp);
+    } else {
+        fifo->find_completions.push_back(comp);
+    }
+    if (!large.empty()) {
+        fifo->large_values.push_back({user_data_lo, user_data_hi, std::move(large)});
+    }
+}
+
 void nomad_fifo_submit_find(
     NomadFifo* fifo,
     const uint8_t* key,
@@ -564,8 +1738,20 @@ void nomad_fifo_submit_find(
     uint64_t user_data_lo,
     uint64_t user_data_hi
 ) {
-    (void)fifo; (void)key; (void)key_len;
-    (void)version; (void)user_data_lo; (void)user_data_hi;
+    if (!fifo) return;
+    fifo_execute_find(fifo, key, key_len, version, user_data_lo, user_data_hi, 0);
+}
+
+void nomad_fifo_submit_find_high_priority(
+    NomadFifo* fifo,
+    const uint8_t* key,
+    size_t key_len,
+    uint64_t version,
+    uint64_t user_data_lo,
+    uint64_t user_data_hi
+) {
+    if (!fifo) return;
+    fifo_execute_find(fifo, key, key_len, version, user_data_lo, user_data_hi, 1);
 }
 
 void nomad_fifo_submit_traverse(
@@ -582,13 +1768,37 @@ void nomad_fifo_submit_traverse(
 }
 
 bool nomad_fifo_poll(NomadFifo* fifo, NomadCompletion* completion_out) {
-    (void)fifo; (void)completion_out;
+    if (!fifo || !completion_out) {
+        return false;
+    }
+
+    std::lock_guard<std::mutex> lock(fifo->mutex);
+    // 高优先级完成先出队
+    if (!fifo->find_completions_high.empty()) {
+        *completion_out = fifo->find_completions_high.front();
+        fifo->find_completions_high.pop_front();
+        return true;
+    }
+    if (!fifo->find_completions.empty()) {
+        *completion_out = fifo->find_completions.front();
+        fifo->find_completions.pop_front();
+        return true;
+    }
     return false;
 }
 
 bool nomad_fifo_poll_traverse(NomadFifo* fifo, NomadCompletion* completion_out) {
-    (void)fifo; (void)completion_out;
-    return false;
+    if (!fifo || !completion_out) {
+        return false;
+    }
+
+    std::lock_guard<std::mutex> lock(fifo->mutex);
+    if (fifo->traverse_completions.empty()) {
+        return false;
+    }
+    *completion_out = fifo->traverse_completions.front();
+    fifo->traverse_completions.pop_front();
+    return true;
 }
 
 bool nomad_fifo_poll_large_value(
@@ -597,6 +1807,29 @@ bool nomad_fifo_poll_large_value(
     uint64_t* user_data_hi,
     NomadBytes* data_out
 ) {
-    (void)fifo; (void)user_data_lo; (void)user_data_hi; (void)data_out;
-    return false;
+    if (!fifo || !user_data_lo || !user_data_hi || !data_out) {
+        return false;
+    }
+
+    std::lock_guard<std::mutex> lock(fifo->mutex);
+    if (fifo->large_values.empty()) {
+        return false;
+    }
+    auto& lv = fifo->large_values.front();
+    *user_data_lo = lv.user_data_lo;
+    *user_data_hi = lv.user_data_hi;
+    *data_out = make_bytes(byte_string_view{lv.data.data(), lv.data.size()});
+    fifo->large_values.pop_front();
+    return true;
+}
+
+size_t nomad_fifo_queue_depth(const NomadFifo* fifo) {
+    if (!fifo) {
+        return 0;
+    }
+
+    std::lock_guard<std::mutex> lock(fifo->mutex);
+    return fifo->find_completions_high.size()
+         + fifo->find_completions.size()
+         + fifo->traverse_completions.size();
 }